@@ -0,0 +1,250 @@
+package main
+
+import stderrors "errors"
+import "flag"
+import "fmt"
+import "golox/interpreter"
+import "golox/repl"
+import "io/ioutil"
+import "net"
+import "os"
+import "os/signal"
+import "syscall"
+
+const usage = `Usage: golox [command] [flags] [script | -] [arguments...]
+
+Commands:
+  run     execute a script (default when a script is given)
+  repl    start the interactive prompt (default when no script is given)
+  tokens  print the lexemes of a script
+  check   report errors in a script without executing it
+  parse   print the syntax tree of a script
+  fmt     format a script
+
+Flags:
+  -e code         use the given code instead of a script
+  -               read the script from standard input
+  --no-color      do not color the output
+  --trace         print each statement as it is executed (not yet implemented)
+  --max-steps n   stop after n execution steps (not yet implemented)
+  -listen address serve REPL sessions on unix:path or tcp:host:port (repl)
+
+Arguments following the script are passed through to the script.
+`
+
+// The golox subcommands, each exits with a status as per sysexits.
+var commands = map[string]func(name string, args []string){
+	"run":    Run,
+	"repl":   Repl,
+	"tokens": Tokens,
+	"check":  Check,
+	"parse":  Unavailable,
+	"fmt":    Unavailable,
+}
+
+func main() {
+	args := os.Args[1:]
+
+	if len(args) == 0 && IsPiped(os.Stdin) {
+		Run("run", []string{"-"})
+		return
+	} else if len(args) == 0 {
+		Repl("repl", args)
+		return
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		os.Exit(0)
+	}
+
+	command, is_command := commands[args[0]]
+
+	if is_command {
+		command(args[0], args[1:])
+	} else {
+		Run("run", args)
+	}
+}
+
+// Run executes the given source, passing the remaining arguments to the script.
+func Run(name string, args []string) {
+	source, script_args := ReadSource(name, args)
+	lox := interpreter.New(interpreter.Options{Args: script_args})
+
+	lox.Run(source)
+
+	if lox.HasHadError() {
+		os.Exit(65)
+	}
+	os.Exit(0)
+}
+
+// Repl starts the interactive prompt, or serves REPL sessions on the address
+// given by -listen.
+func Repl(name string, args []string) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+
+	address := flags.String("listen", "", "serve REPL sessions on the address")
+
+	err := flags.Parse(args)
+
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	} else if err != nil {
+		os.Exit(64)
+	}
+
+	if flags.NArg() != 0 {
+		Usage()
+	}
+
+	if *address == "" {
+		err = repl.RunPrompt(os.Stdin, os.Stdout, os.Stderr)
+	} else {
+		err = Serve(*address)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(74)
+	}
+	os.Exit(0)
+}
+
+// Serve REPL sessions on the given address until interrupted.
+func Serve(address string) error {
+	listener, err := repl.Listen(address)
+
+	if err != nil {
+		return err
+	}
+
+	// Close the listener on interrupt, removing Unix sockets
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-interrupts
+		listener.Close()
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving REPL sessions on %s\n", listener.Addr())
+
+	err = repl.Serve(listener)
+
+	if stderrors.Is(err, net.ErrClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// Tokens prints the lexemes of the given source.
+func Tokens(name string, args []string) {
+	source, _ := ReadSource(name, args)
+	lox := interpreter.New(interpreter.Options{})
+
+	for _, lexeme := range lox.Lex(source) {
+		fmt.Println(lexeme)
+	}
+
+	if lox.HasHadError() {
+		os.Exit(65)
+	}
+	os.Exit(0)
+}
+
+// Check reports the errors in the given source without executing it.
+func Check(name string, args []string) {
+	source, _ := ReadSource(name, args)
+	lox := interpreter.New(interpreter.Options{})
+
+	lox.Lex(source)
+
+	if lox.HasHadError() {
+		os.Exit(65)
+	}
+	os.Exit(0)
+}
+
+// Unavailable reports that the given command is not yet implemented.
+func Unavailable(name string, args []string) {
+	fmt.Fprintf(os.Stderr, "golox %s: not yet implemented, requires the parser\n", name)
+	os.Exit(69)
+}
+
+// Return true if the given file is not a terminal, e.g. a pipe or a file.
+func IsPiped(file *os.File) bool {
+	info, err := file.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// Print the usage and exit with a usage error.
+func Usage() {
+	fmt.Fprint(os.Stderr, usage)
+	os.Exit(64)
+}
+
+// ReadSource parses the common flags and returns the source given by -e, the
+// script path, or standard input if the script path is "-".
+// The arguments following the script, or following -e, are returned for the
+// script.
+func ReadSource(name string, args []string) (string, []string) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+
+	code := flags.String("e", "", "use the given code instead of a script")
+
+	// No output is colored, so --no-color has nothing to disable
+	flags.Bool("no-color", false, "do not color the output")
+
+	// Pending until the evaluator exists, reported as unavailable when given
+	flags.Bool("trace", false, "print each statement as it is executed")
+	flags.Int("max-steps", 0, "stop after executing the given number of steps")
+
+	err := flags.Parse(args)
+
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	} else if err != nil {
+		os.Exit(64)
+	}
+
+	has_code := false
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "e":
+			has_code = true
+		case "trace", "max-steps":
+			fmt.Fprintf(os.Stderr, "golox %s: --%s not yet implemented, requires the evaluator\n", name, f.Name)
+			os.Exit(69)
+		}
+	})
+
+	if has_code {
+		return *code, flags.Args()
+	}
+
+	if flags.NArg() == 0 {
+		Usage()
+	}
+
+	var source []byte
+
+	if path := flags.Arg(0); path == "-" {
+		source, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		source, err = ioutil.ReadFile(path)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(74)
+	}
+
+	return string(source), flags.Args()[1:]
+}
@@ -0,0 +1,42 @@
+package main
+
+import "io/ioutil"
+import "path/filepath"
+import "strings"
+import "testing"
+
+func Test_ReadSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.lox")
+
+	if err := ioutil.WriteFile(path, []byte("print 1;"), 0600); err != nil {
+		t.Fatalf("error writing script: %s", err)
+	}
+
+	cases := map[string]struct {
+		source string
+		args   string
+	}{
+		path:                      {"print 1;", ""},
+		path + " a b":             {"print 1;", "a b"},
+		path + " -e x":            {"print 1;", "-e x"},
+		"-e print(2); a b":        {"print(2);", "a b"},
+		"--no-color -e print(2);": {"print(2);", ""},
+		"-e print(2);":            {"print(2);", ""},
+	}
+
+	for args, expected := range cases {
+		source, script_args := ReadSource("run", strings.Fields(args))
+
+		if source != expected.source || strings.Join(script_args, " ") != expected.args {
+			t.Logf(
+				"ReadSource(%q) expects (%q, %q) received (%q, %q)",
+				args,
+				expected.source,
+				expected.args,
+				source,
+				strings.Join(script_args, " "),
+			)
+			t.Fail()
+		}
+	}
+}
@@ -1,12 +1,18 @@
 package errors
 
 import "fmt"
+import "os"
+import "sync"
 
-// Global error state
+// Global error state, prefer a Reporter per interpreter
 var has_had_error = false
+var has_had_error_lock sync.Mutex
 
 // Return true if an error has been reached while running the lox source.
 func HasHadError() bool {
+	has_had_error_lock.Lock()
+	defer has_had_error_lock.Unlock()
+
 	return has_had_error
 }
 
@@ -15,13 +21,18 @@ func Error(et ErrorType, line int, message string) {
 }
 
 func Report(et ErrorType, line int, where string, message string) {
+	has_had_error_lock.Lock()
 	has_had_error = true
+	has_had_error_lock.Unlock()
+
+	fmt.Fprintln(os.Stderr, Format(et, line, where, message))
+}
 
+// Format returns the error message for the given error.
+func Format(et ErrorType, line int, where string, message string) string {
 	if where != "" {
 		where = fmt.Sprintf(" (%s)", where)
 	}
 
-	const base = "%s%s: %d: %s"
-
-	fmt.Printf("%s%s: line %d: %s\n", et, where, line, message)
+	return fmt.Sprintf("%s%s: line %d: %s", et, where, line, message)
 }
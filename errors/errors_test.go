@@ -1,5 +1,8 @@
 package errors
 
+import "fmt"
+import "io/ioutil"
+import "os"
 import "testing"
 
 func Test_HasHadError(t *testing.T) {
@@ -36,13 +39,13 @@ func Test_Error(t *testing.T) {
 	has_had_error = false
 }
 
-func ExampleError_lox_error() {
-	Error(LoxError, 66, "my error")
+func ExampleFormat_lox_error() {
+	fmt.Println(Format(LoxError, 66, "", "my error"))
 	// Output: LoxError: line 66: my error
 }
 
-func ExampleError_syntax_error() {
-	Error(SyntaxError, 999, "oh no!")
+func ExampleFormat_syntax_error() {
+	fmt.Println(Format(SyntaxError, 999, "", "oh no!"))
 	// Output: SyntaxError: line 999: oh no!
 }
 
@@ -63,12 +66,64 @@ func Test_Report(t *testing.T) {
 	has_had_error = false
 }
 
-func ExampleReport_lox_error() {
-	Report(LoxError, 66, "somewhere", "my error")
+func ExampleFormat_lox_error_where() {
+	fmt.Println(Format(LoxError, 66, "somewhere", "my error"))
 	// Output: LoxError (somewhere): line 66: my error
 }
 
-func ExampleReport_syntax_error() {
-	Report(SyntaxError, 999, "somewhen", "oh no!")
+func ExampleFormat_syntax_error_where() {
+	fmt.Println(Format(SyntaxError, 999, "somewhen", "oh no!"))
 	// Output: SyntaxError (somewhen): line 999: oh no!
 }
+
+// Return what the given function writes to stderr.
+func CaptureStderr(t *testing.T, f func()) string {
+	reader, writer, err := os.Pipe()
+
+	if err != nil {
+		t.Fatalf("error creating pipe: %s", err)
+	}
+
+	stderr := os.Stderr
+	os.Stderr = writer
+
+	f()
+
+	os.Stderr = stderr
+	writer.Close()
+
+	output, err := ioutil.ReadAll(reader)
+
+	if err != nil {
+		t.Fatalf("error reading pipe: %s", err)
+	}
+
+	return string(output)
+}
+
+func Test_Error_Stderr(t *testing.T) {
+	cases := map[string]func(){
+		"LoxError: line 66: my error\n": func() {
+			Error(LoxError, 66, "my error")
+		},
+		"SyntaxError: line 999: oh no!\n": func() {
+			Error(SyntaxError, 999, "oh no!")
+		},
+		"LoxError (somewhere): line 66: my error\n": func() {
+			Report(LoxError, 66, "somewhere", "my error")
+		},
+		"SyntaxError (somewhen): line 999: oh no!\n": func() {
+			Report(SyntaxError, 999, "somewhen", "oh no!")
+		},
+	}
+
+	for expected, f := range cases {
+		if output := CaptureStderr(t, f); output != expected {
+			t.Logf("expect %q on stderr, received %q", expected, output)
+			t.Fail()
+		}
+	}
+
+	// reset
+	has_had_error = false
+}
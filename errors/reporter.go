@@ -0,0 +1,58 @@
+package errors
+
+import "fmt"
+import "io"
+
+// SourceError is an error reached while running lox source.
+type SourceError struct {
+	Type    ErrorType
+	Line    int
+	Where   string
+	Message string
+}
+
+func (e *SourceError) Error() string {
+	return Format(e.Type, e.Line, e.Where, e.Message)
+}
+
+// Reporter reports errors to its writer, recording the errors reached
+// independently of the global error state.
+type Reporter struct {
+	writer        io.Writer
+	has_had_error bool
+	errors        []*SourceError
+}
+
+// NewReporter returns a reporter writing errors to the given writer.
+func NewReporter(writer io.Writer) *Reporter {
+	return &Reporter{writer: writer}
+}
+
+// Return true if an error has been reported since the last reset.
+func (r *Reporter) HasHadError() bool {
+	return r.has_had_error
+}
+
+// Return the errors reported since the last reset.
+func (r *Reporter) Errors() []*SourceError {
+	return r.errors
+}
+
+// Reset forgets any errors reported.
+func (r *Reporter) Reset() {
+	r.has_had_error = false
+	r.errors = nil
+}
+
+func (r *Reporter) Error(et ErrorType, line int, message string) {
+	r.Report(et, line, "", message)
+}
+
+func (r *Reporter) Report(et ErrorType, line int, where string, message string) {
+	err := &SourceError{et, line, where, message}
+
+	r.has_had_error = true
+	r.errors = append(r.errors, err)
+
+	fmt.Fprintln(r.writer, err)
+}
@@ -0,0 +1,46 @@
+package errors
+
+import "bytes"
+import "testing"
+
+func Test_Reporter(t *testing.T) {
+	var output bytes.Buffer
+	reporter := NewReporter(&output)
+
+	// assert HasHadError is false initially
+	if reporter.HasHadError() {
+		t.Fatalf("expect reporter.HasHadError() to be false")
+	}
+
+	// have an error
+	reporter.Error(SyntaxError, 999, "oh no!")
+	reporter.Report(LoxError, 66, "somewhere", "my error")
+
+	if !reporter.HasHadError() {
+		t.Fatalf("expect reporter.HasHadError() to be true")
+	}
+
+	// assert the global error state is untouched
+	if HasHadError() {
+		t.Fatalf("expect HasHadError() to be false")
+	}
+
+	expected := "SyntaxError: line 999: oh no!\nLoxError (somewhere): line 66: my error\n"
+
+	if output.String() != expected {
+		t.Fatalf("expect output %q received %q", expected, output.String())
+	}
+
+	errors := reporter.Errors()
+
+	if len(errors) != 2 || errors[0].Line != 999 || errors[1].Where != "somewhere" {
+		t.Fatalf("expect the reported errors, received %v", errors)
+	}
+
+	// reset
+	reporter.Reset()
+
+	if reporter.HasHadError() || len(reporter.Errors()) != 0 {
+		t.Fatalf("expect no errors after Reset()")
+	}
+}
@@ -1,21 +1,67 @@
-package main
+// Package golox embeds the lox interpreter in Go programs.
+package golox
 
-import "fmt"
+import "golox/errors"
 import "golox/interpreter"
-import "golox/repl"
-import "os"
-
-func main() {
-	args := os.Args[1:]
-
-	if len(args) > 1 {
-		fmt.Println("Usage: golox [script]")
-		os.Exit(64)
-	} else if len(args) == 1 {
-		interpreter.RunFile(args[0])
-		os.Exit(0)
-	} else {
-		repl.RunPrompt()
-		os.Exit(0)
+import "io"
+import "io/ioutil"
+import "strings"
+
+// Options configures the streams of a VM.
+type Options struct {
+	// Read by the program, standard input if nil
+	Stdin io.Reader
+
+	// Written to by the program, e.g. by print, standard output if nil
+	Stdout io.Writer
+
+	// Written to with errors as they are reached, discarded if nil as the
+	// errors are returned by Eval
+	Stderr io.Writer
+}
+
+// VM runs lox source for a Go program, keeping its state between evaluations.
+type VM struct {
+	interpreter *interpreter.Interpreter
+}
+
+// Error is returned by Eval, listing the errors reached.
+type Error struct {
+	Errors []*errors.SourceError
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, len(e.Errors))
+
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
 	}
+
+	return strings.Join(messages, "\n")
+}
+
+// New returns a VM with the given options.
+func New(options Options) *VM {
+	if options.Stderr == nil {
+		options.Stderr = ioutil.Discard
+	}
+
+	return &VM{
+		interpreter: interpreter.New(interpreter.Options{
+			Stdin:  options.Stdin,
+			Stdout: options.Stdout,
+			Stderr: options.Stderr,
+		}),
+	}
+}
+
+// Eval runs the given source, returning an *Error if errors are reached.
+func (vm *VM) Eval(source string) error {
+	vm.interpreter.Run(source)
+
+	if vm.interpreter.HasHadError() {
+		return &Error{Errors: vm.interpreter.Errors()}
+	}
+
+	return nil
 }
@@ -0,0 +1,117 @@
+package golox
+
+import "bytes"
+import "fmt"
+import "strings"
+import "testing"
+
+func Test_Eval(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	vm := New(Options{Stdout: &stdout, Stderr: &stderr})
+
+	if err := vm.Eval("print 1;"); err != nil {
+		t.Fatalf("Eval() failed with error: %s", err)
+	}
+
+	if stdout.Len() == 0 {
+		t.Fatalf("expect output on stdout")
+	}
+
+	err := vm.Eval("print @;\nprint #;")
+
+	if err == nil {
+		t.Fatalf("Eval() expects error")
+	}
+
+	lox_err, ok := err.(*Error)
+
+	if !ok || len(lox_err.Errors) != 2 || lox_err.Errors[1].Line != 2 {
+		t.Fatalf("Eval() expects an *Error listing both errors, received %#v", err)
+	}
+
+	expected := "SyntaxError: line 1: Unexpected character '@'\n" +
+		"SyntaxError: line 2: Unexpected character '#'"
+
+	if err.Error() != expected {
+		t.Fatalf("expect error %q received %q", expected, err.Error())
+	}
+
+	if stderr.String() != expected+"\n" {
+		t.Fatalf("expect errors on stderr, received %q", stderr.String())
+	}
+
+	// assert errors do not carry over to the next evaluation
+	if err := vm.Eval("print 2;"); err != nil {
+		t.Fatalf("Eval() failed with error: %s", err)
+	}
+}
+
+func Test_Eval_DiscardsErrors(t *testing.T) {
+	var stdout bytes.Buffer
+	vm := New(Options{Stdout: &stdout})
+
+	if err := vm.Eval("@"); err == nil {
+		t.Fatalf("Eval() expects error")
+	}
+
+	if stdout.Len() != 0 {
+		t.Fatalf("expect no output on stdout, received %q", stdout.String())
+	}
+}
+
+// The number of VMs run concurrently by Test_Eval_Concurrent.
+const concurrent_vms = 200
+
+func Test_Eval_Concurrent(t *testing.T) {
+	type result struct {
+		id     int
+		stdout string
+		stderr string
+		err    error
+	}
+
+	results := make(chan result, concurrent_vms)
+
+	for id := 0; id < concurrent_vms; id++ {
+		go func(id int) {
+			var stdout, stderr bytes.Buffer
+			vm := New(Options{Stdout: &stdout, Stderr: &stderr})
+
+			// Odd VMs reach an error on the line given by their id
+			source := fmt.Sprintf("print %d;", id)
+			if id%2 == 1 {
+				source += strings.Repeat("\n", id) + "@"
+			}
+
+			err := vm.Eval(source)
+
+			results <- result{id, stdout.String(), stderr.String(), err}
+		}(id)
+	}
+
+	for i := 0; i < concurrent_vms; i++ {
+		r := <-results
+
+		expected_stdout := fmt.Sprintf("Lexeme(type=LiteralInteger, lexeme=\"%d\", literal=%d)", r.id, r.id)
+
+		if strings.Count(r.stdout, "LiteralInteger") != 1 || !strings.Contains(r.stdout, expected_stdout) {
+			t.Logf("VM %d expects only its own output, received %q", r.id, r.stdout)
+			t.Fail()
+		}
+
+		expected_stderr := ""
+		if r.id%2 == 1 {
+			expected_stderr = fmt.Sprintf("SyntaxError: line %d: Unexpected character '@'\n", r.id+1)
+		}
+
+		if r.stderr != expected_stderr {
+			t.Logf("VM %d expects errors %q, received %q", r.id, expected_stderr, r.stderr)
+			t.Fail()
+		}
+
+		if (r.err != nil) != (r.id%2 == 1) {
+			t.Logf("VM %d expects error %t, received %v", r.id, r.id%2 == 1, r.err)
+			t.Fail()
+		}
+	}
+}
@@ -4,6 +4,7 @@ package interpreter
 import "fmt"
 import "golox/lexer"
 import "golox/errors"
+import "io"
 import "io/ioutil"
 import "os"
 
@@ -12,22 +13,101 @@ func RunFile(path string) {
 	source, err := ioutil.ReadFile(path)
 
 	if err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(74)
 	}
 
-	Run(string(source))
+	interpreter := New(Options{})
+	interpreter.Run(string(source))
 
-	if errors.HasHadError() {
+	if interpreter.HasHadError() {
 		os.Exit(65)
 	}
 }
 
 // Run lexes, parses, and excecutes the given source code.
 func Run(source string) {
-	lexemes := lexer.Lex(source)
+	New(Options{}).Run(source)
+}
+
+// Options configures the streams of an interpreter.
+// Streams which are nil default to the standard streams.
+type Options struct {
+	// Read by the program
+	Stdin io.Reader
+
+	// Written to by the program, e.g. by print
+	Stdout io.Writer
+
+	// Written to with errors
+	Stderr io.Writer
+
+	// The arguments passed to the script
+	Args []string
+}
+
+// Interpreter runs lox source, keeping its state between runs, e.g. for the
+// REPL.
+type Interpreter struct {
+	stdin    io.Reader
+	stdout   io.Writer
+	reporter *errors.Reporter
+	args     []string
+}
 
+// New returns an interpreter with the given options.
+func New(options Options) *Interpreter {
+	if options.Stdin == nil {
+		options.Stdin = os.Stdin
+	}
+
+	if options.Stdout == nil {
+		options.Stdout = os.Stdout
+	}
+
+	if options.Stderr == nil {
+		options.Stderr = os.Stderr
+	}
+
+	return &Interpreter{
+		stdin:    options.Stdin,
+		stdout:   options.Stdout,
+		reporter: errors.NewReporter(options.Stderr),
+		args:     options.Args,
+	}
+}
+
+// Run lexes, parses, and excecutes the given source code.
+// Errors reached by previous runs are forgotten.
+func (i *Interpreter) Run(source string) {
+	i.Execute(i.Lex(source))
+}
+
+// Lex returns the lexemes of the given source, reporting errors as a run would.
+func (i *Interpreter) Lex(source string) []lexer.Lexeme {
+	i.reporter.Reset()
+
+	return lexer.LexWith(source, i.reporter)
+}
+
+// Execute the given lexemes.
+func (i *Interpreter) Execute(lexemes []lexer.Lexeme) {
 	for _, lexeme := range lexemes {
-		fmt.Println(lexeme)
+		fmt.Fprintln(i.stdout, lexeme)
 	}
 }
+
+// Return true if an error was reached by the last run.
+func (i *Interpreter) HasHadError() bool {
+	return i.reporter.HasHadError()
+}
+
+// Return the arguments passed to the script.
+func (i *Interpreter) Args() []string {
+	return i.args
+}
+
+// Return the errors reached by the last run.
+func (i *Interpreter) Errors() []*errors.SourceError {
+	return i.reporter.Errors()
+}
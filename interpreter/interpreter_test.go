@@ -1,7 +1,9 @@
 package interpreter
 
+import "bytes"
 import "os"
 import "os/exec"
+import "strings"
 import "testing"
 
 const GOLOX_TEST_RUNFILE = "GOLOX_TEST_RUNFILE"
@@ -27,3 +29,56 @@ func Test_RunFile_FileNotFound(t *testing.T) {
 }
 
 // TODO Test_Run
+
+func Test_Interpreter_Run(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	interpreter := New(Options{Stdout: &stdout, Stderr: &stderr})
+
+	// assert HasHadError is false initially
+	if interpreter.HasHadError() {
+		t.Fatalf("expect interpreter.HasHadError() to be false")
+	}
+
+	interpreter.Run("print @;")
+
+	if !interpreter.HasHadError() {
+		t.Fatalf("expect interpreter.HasHadError() to be true")
+	}
+
+	// assert errors are written to stderr only
+	if stderr.String() != "SyntaxError: line 1: Unexpected character '@'\n" {
+		t.Fatalf("expect the error on stderr, received %q", stderr.String())
+	}
+
+	if strings.Contains(stdout.String(), "Error") {
+		t.Fatalf("expect no errors on stdout, received %q", stdout.String())
+	}
+
+	// assert the error does not carry over to the next run
+	stdout.Reset()
+	interpreter.Run("print 1;")
+
+	if interpreter.HasHadError() {
+		t.Fatalf("expect interpreter.HasHadError() to be false after a run without errors")
+	}
+
+	expected := "Lexeme(type=Print, lexeme=\"print\")\n" +
+		"Lexeme(type=LiteralInteger, lexeme=\"1\", literal=1)\n" +
+		"Lexeme(type=Semicolon, lexeme=\";\")\n"
+
+	if stdout.String() != expected {
+		t.Fatalf("expect output %q on stdout, received %q", expected, stdout.String())
+	}
+}
+
+func Test_Interpreter_Args(t *testing.T) {
+	interpreter := New(Options{Args: []string{"a", "b"}})
+
+	if strings.Join(interpreter.Args(), " ") != "a b" {
+		t.Fatalf("expect the arguments \"a b\", received %q", interpreter.Args())
+	}
+
+	if len(New(Options{}).Args()) != 0 {
+		t.Fatalf("expect no arguments by default")
+	}
+}
@@ -6,10 +6,11 @@ import "strconv"
 
 // Lexeme stores the information for a lexeme.
 // Lexeme.lexeme:
-//   Identifier    => the name as is
-//   LiteralString => the string literal enclosed in quotes
-//   LiteralNumber => the number as is, float64 as a string
-//   Otherwise     => as expected
+//   Identifier     => the name as is
+//   LiteralString  => the string literal enclosed in quotes
+//   LiteralNumber  => the number as is, float64 as a string
+//   LiteralInteger => the integer as is, int64 as a string
+//   Otherwise      => as expected
 type Lexeme struct {
 	lexeme_type LexemeType
 	lexeme      string
@@ -20,7 +21,7 @@ type Lexeme struct {
 // If the given lexeme is not is not a Lox literal an error is returned.
 func (l Lexeme) Literal() (string, error) {
 	switch l.lexeme_type {
-	case Identifier, LiteralString, LiteralNumber:
+	case Identifier, LiteralString, LiteralNumber, LiteralInteger:
 		return l.lexeme, nil
 	default:
 		return "", errors.New(fmt.Sprintf("lexeme %s is not a literal", l))
@@ -49,10 +50,11 @@ func (l Lexeme) ParseString() (string, error) {
 }
 
 // Return the given lexeme's literal value as a float64.
-// If the given lexeme is not a LiteralNumber an error is returned.
+// If the given lexeme is not a LiteralNumber or a LiteralInteger an error is
+// returned.
 // If the given lexeme fails strconv.ParseFloat the error is propogated.
 func (l Lexeme) ParseFloat() (float64, error) {
-	if l.lexeme_type != LiteralNumber {
+	if l.lexeme_type != LiteralNumber && l.lexeme_type != LiteralInteger {
 		msg := fmt.Sprintf(
 			"lexeme of type '%s' has no numeric literal",
 			l.lexeme_type.String(),
@@ -65,13 +67,31 @@ func (l Lexeme) ParseFloat() (float64, error) {
 	return strconv.ParseFloat(literal, 64)
 }
 
+// Return the given lexeme's literal value as an int64.
+// If the given lexeme is not a LiteralInteger an error is returned.
+// If the given lexeme fails strconv.ParseInt the error is propogated, e.g. if
+// the integer is out of range.
+func (l Lexeme) ParseInt() (int64, error) {
+	if l.lexeme_type != LiteralInteger {
+		msg := fmt.Sprintf(
+			"lexeme of type '%s' has no integer literal",
+			l.lexeme_type.String(),
+		)
+		return 0, errors.New(msg)
+	}
+
+	literal, _ := l.Literal()
+
+	return strconv.ParseInt(literal, 10, 64)
+}
+
 // Cast the given lexeme to a string.
 func (l Lexeme) String() string {
 	const base = "Lexeme(type=%s, lexeme=\"%s\")"
 	const literal = "Lexeme(type=%s, lexeme=\"%s\", literal=%s)"
 
 	switch l.lexeme_type {
-	case Identifier, LiteralString, LiteralNumber:
+	case Identifier, LiteralString, LiteralNumber, LiteralInteger:
 		val, err := l.Literal()
 
 		if err != nil {
@@ -13,6 +13,8 @@ func Test_Literal(t *testing.T) {
 		{LiteralNumber, "1.0", 1}:               "1.0",
 		{LiteralNumber, "1.000", 1}:             "1.000",
 		{LiteralNumber, "12.34", 1}:             "12.34",
+		{LiteralInteger, "1", 1}:                "1",
+		{LiteralInteger, "1234", 1}:             "1234",
 	}
 
 	for lexeme, expected := range has_literal {
@@ -166,6 +168,8 @@ func Test_ParseFloat(t *testing.T) {
 		{LiteralNumber, "00.0001", 1}: 0.0001,
 		{LiteralNumber, "3", 1}:       3.0,
 		{LiteralNumber, "003", 1}:     3.0,
+		{LiteralInteger, "3", 1}:      3.0,
+		{LiteralInteger, "003", 1}:    3.0,
 	}
 
 	for lexeme, expected := range wellformed_floats {
@@ -188,6 +192,70 @@ func Test_ParseFloat(t *testing.T) {
 	}
 }
 
+func Test_ParseInt(t *testing.T) {
+	// Invalid: not a LiteralInteger
+	non_literal_integers := []Lexeme{
+		{If, "if", 1},
+		{Bang, "!", 1},
+		{Identifier, "foobar", 1},
+		{LiteralString, "\"12\"", 1},
+		{LiteralNumber, "12.0", 1},
+	}
+
+	for _, lexeme := range non_literal_integers {
+		_, err := lexeme.ParseInt()
+
+		if err == nil {
+			t.Logf("%s.ParseInt() expects error", lexeme)
+			t.Fail()
+		}
+	}
+
+	// Invalid: out of range
+	out_of_range := []Lexeme{
+		{LiteralInteger, "9223372036854775808", 1},
+		{LiteralInteger, "99999999999999999999999", 1},
+	}
+
+	for _, lexeme := range out_of_range {
+		_, err := lexeme.ParseInt()
+
+		if err == nil {
+			t.Logf("%s.ParseInt() expects error from strconv", lexeme)
+			t.Fail()
+		}
+	}
+
+	// Valid
+	wellformed_integers := map[Lexeme]int64{
+		{LiteralInteger, "0", 1}:                   0,
+		{LiteralInteger, "000", 1}:                 0,
+		{LiteralInteger, "3", 1}:                   3,
+		{LiteralInteger, "003", 1}:                 3,
+		{LiteralInteger, "9007199254740993", 1}:    9007199254740993,
+		{LiteralInteger, "9223372036854775807", 1}: 9223372036854775807,
+	}
+
+	for lexeme, expected := range wellformed_integers {
+		val, err := lexeme.ParseInt()
+
+		if err != nil {
+			t.Logf("%s.ParseInt() failed with error: %s", lexeme, err)
+			t.Fail()
+		}
+
+		if val != expected {
+			t.Logf(
+				"%s.ParseInt() expects '%d' but recieved '%d'",
+				lexeme,
+				expected,
+				val,
+			)
+			t.Fail()
+		}
+	}
+}
+
 func Test_String(t *testing.T) {
 	cases := map[Lexeme]string{
 		// Single-character lexemes
@@ -203,6 +271,7 @@ func Test_String(t *testing.T) {
 		{Identifier, "foobar", 1}:       "Lexeme(type=Identifier, lexeme=\"foobar\", literal=foobar)",
 		{LiteralString, "\"a str\"", 1}: "Lexeme(type=LiteralString, lexeme=\"\\\"a str\\\"\", literal=\"a str\")",
 		{LiteralNumber, "12.34", 1}:     "Lexeme(type=LiteralNumber, lexeme=\"12.34\", literal=12.34)",
+		{LiteralInteger, "1234", 1}:     "Lexeme(type=LiteralInteger, lexeme=\"1234\", literal=1234)",
 	}
 
 	for lexeme, expected := range cases {
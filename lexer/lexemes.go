@@ -1,5 +1,7 @@
 package lexer
 
+import "sort"
+
 type LexemeType int64
 
 const (
@@ -10,6 +12,8 @@ const (
 	RightParenthesis
 	LeftBrace
 	RightBrace
+	LeftBracket
+	RightBracket
 	Comma
 	Dot
 	Minus
@@ -17,6 +21,9 @@ const (
 	Semicolon
 	Slash
 	Star
+	Question
+	Colon
+	Percent
 
 	// One-or-two character lexemes
 	Bang
@@ -27,17 +34,24 @@ const (
 	GreaterEqual
 	Less
 	LessEqual
+	Arrow
+	TildeSlash
 
 	// Literals
 	Identifier
 	LiteralString
 	LiteralNumber
+	LiteralInteger
 
 	// Keywords
 	And
+	Break
+	Catch
 	Class
+	Continue
 	Else
 	False
+	Finally
 	Fun
 	For
 	If
@@ -47,7 +61,9 @@ const (
 	Return
 	Super
 	This
+	Throw
 	True
+	Try
 	Var
 	While
 
@@ -55,22 +71,41 @@ const (
 )
 
 var keywords = map[string]LexemeType{
-	And.String():    And,
-	Class.String():  Class,
-	Else.String():   Else,
-	False.String():  False,
-	For.String():    For,
-	Fun.String():    Fun,
-	If.String():     If,
-	Nil.String():    Nil,
-	Or.String():     Or,
-	Print.String():  Print,
-	Return.String(): Return,
-	Super.String():  Super,
-	This.String():   This,
-	True.String():   True,
-	Var.String():    Var,
-	While.String():  While,
+	"and":      And,
+	"break":    Break,
+	"catch":    Catch,
+	"class":    Class,
+	"continue": Continue,
+	"else":     Else,
+	"false":    False,
+	"finally":  Finally,
+	"for":      For,
+	"fun":      Fun,
+	"if":       If,
+	"nil":      Nil,
+	"or":       Or,
+	"print":    Print,
+	"return":   Return,
+	"super":    Super,
+	"this":     This,
+	"throw":    Throw,
+	"true":     True,
+	"try":      Try,
+	"var":      Var,
+	"while":    While,
+}
+
+// Keywords returns the reserved words of lox, sorted.
+func Keywords() []string {
+	words := make([]string, 0, len(keywords))
+
+	for word := range keywords {
+		words = append(words, word)
+	}
+
+	sort.Strings(words)
+
+	return words
 }
 
 // Return the string form of the LexemeType.
@@ -86,6 +121,10 @@ func (lexeme_type LexemeType) String() string {
 		return "LeftBrace"
 	case RightBrace:
 		return "RightBrace"
+	case LeftBracket:
+		return "LeftBracket"
+	case RightBracket:
+		return "RightBracket"
 	case Comma:
 		return "Comma"
 	case Dot:
@@ -100,6 +139,12 @@ func (lexeme_type LexemeType) String() string {
 		return "Slash"
 	case Star:
 		return "Star"
+	case Question:
+		return "Question"
+	case Colon:
+		return "Colon"
+	case Percent:
+		return "Percent"
 	case Bang:
 		return "Bang"
 	case BangEqual:
@@ -116,20 +161,34 @@ func (lexeme_type LexemeType) String() string {
 		return "Less"
 	case LessEqual:
 		return "LessEqual"
+	case Arrow:
+		return "Arrow"
+	case TildeSlash:
+		return "TildeSlash"
 	case Identifier:
 		return "Identifier"
 	case LiteralString:
 		return "LiteralString"
 	case LiteralNumber:
 		return "LiteralNumber"
+	case LiteralInteger:
+		return "LiteralInteger"
 	case And:
 		return "And"
+	case Break:
+		return "Break"
+	case Catch:
+		return "Catch"
 	case Class:
 		return "Class"
+	case Continue:
+		return "Continue"
 	case Else:
 		return "Else"
 	case False:
 		return "False"
+	case Finally:
+		return "Finally"
 	case Fun:
 		return "Fun"
 	case For:
@@ -148,8 +207,12 @@ func (lexeme_type LexemeType) String() string {
 		return "Super"
 	case This:
 		return "This"
+	case Throw:
+		return "Throw"
 	case True:
 		return "True"
+	case Try:
+		return "Try"
 	case Var:
 		return "Var"
 	case While:
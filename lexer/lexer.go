@@ -2,6 +2,8 @@ package lexer
 
 import "fmt"
 import e "golox/errors"
+import "io/ioutil"
+import "strings"
 
 // Store the lexer state.
 type Lexer struct {
@@ -11,18 +13,30 @@ type Lexer struct {
 	start   int
 	current int
 	line    int
+
+	// Reports errors, the global error state is used if nil
+	reporter *e.Reporter
 }
 
 // Lex returns the list of tokens in the given lox source code.
 func Lex(source string) []Lexeme {
+	return LexWith(source, nil)
+}
+
+// LexWith returns the list of tokens in the given lox source code, reporting
+// errors to the given reporter.
+func LexWith(source string, reporter *e.Reporter) []Lexeme {
 	lexer := Lexer{
-		source:  source,
-		lexemes: make([]Lexeme, 0),
-		start:   0,
-		current: 0,
-		line:    1,
+		source:   source,
+		lexemes:  make([]Lexeme, 0),
+		start:    0,
+		current:  0,
+		line:     1,
+		reporter: reporter,
 	}
 
+	lexer.ConsumeShebang()
+
 	for !lexer.IsAtEnd() {
 		lexer.ConsumeLexeme()
 	}
@@ -30,6 +44,55 @@ func Lex(source string) []Lexeme {
 	return lexer.lexemes
 }
 
+// IsComplete returns false if the given lox source code ends within a string or
+// a multi-line comment, or has unclosed parentheses, braces, or brackets, i.e.
+// more source is expected to follow.
+// Errors are not reported.
+func IsComplete(source string) bool {
+	reporter := e.NewReporter(ioutil.Discard)
+
+	lexer := Lexer{
+		source:   source,
+		lexemes:  make([]Lexeme, 0),
+		start:    0,
+		current:  0,
+		line:     1,
+		reporter: reporter,
+	}
+
+	lexer.ConsumeShebang()
+
+	for !lexer.IsAtEnd() {
+		start := lexer.current
+
+		reporter.Reset()
+		lexer.ConsumeLexeme()
+
+		is_string := source[start] == '"'
+		is_comment := strings.HasPrefix(source[start:], "/*")
+
+		if (is_string || is_comment) && reporter.HasHadError() {
+			return false
+		}
+	}
+
+	depth := 0
+
+	for _, lexeme := range lexer.lexemes {
+		switch lexeme.lexeme_type {
+		case LeftParenthesis, LeftBrace, LeftBracket:
+			depth++
+		case RightParenthesis, RightBrace, RightBracket:
+			// An unmatched closer is an error, not the close of a later opener
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+
+	return depth == 0
+}
+
 // Consume the next lexeme and update the lexer state accordingly.
 func (l *Lexer) ConsumeLexeme() {
 	c := l.Advance()
@@ -43,6 +106,10 @@ func (l *Lexer) ConsumeLexeme() {
 		l.AddLexeme(LeftBrace)
 	case '}':
 		l.AddLexeme(RightBrace)
+	case '[':
+		l.AddLexeme(LeftBracket)
+	case ']':
+		l.AddLexeme(RightBracket)
 	case ',':
 		l.AddLexeme(Comma)
 	case '.':
@@ -55,14 +122,31 @@ func (l *Lexer) ConsumeLexeme() {
 		l.AddLexeme(Semicolon)
 	case '*':
 		l.AddLexeme(Star)
+	case '%':
+		l.AddLexeme(Percent)
+	case '?':
+		l.AddLexeme(Question)
+	case ':':
+		l.AddLexeme(Colon)
 	case '!':
 		l.AddLexemeWithLookAhead('=', BangEqual, Bang)
 	case '=':
-		l.AddLexemeWithLookAhead('=', EqualEqual, Equal)
+		if l.Match('>') {
+			l.AddLexeme(Arrow)
+		} else {
+			l.AddLexemeWithLookAhead('=', EqualEqual, Equal)
+		}
 	case '<':
 		l.AddLexemeWithLookAhead('=', LessEqual, Less)
 	case '>':
 		l.AddLexemeWithLookAhead('=', GreaterEqual, Greater)
+	case '~':
+		// Integer division, as "//" begins a comment
+		if l.Match('/') {
+			l.AddLexeme(TildeSlash)
+		} else {
+			l.Error(l.line, fmt.Sprintf("Unexpected character '%c'", c))
+		}
 	case '/':
 		if l.Match('/') {
 			l.ConsumeComment()
@@ -83,9 +167,20 @@ func (l *Lexer) ConsumeLexeme() {
 	case '\n':
 		l.line++
 	default:
-		e.Error(e.SyntaxError, l.line, fmt.Sprintf("Unexpected character '%c'", c))
+		l.Error(l.line, fmt.Sprintf("Unexpected character '%c'", c))
+	}
+
+	l.start = l.current
+}
+
+// Consume the shebang line, e.g. "#!/usr/bin/env golox", if the source starts
+// with one.
+func (l *Lexer) ConsumeShebang() {
+	if l.current != 0 || l.LookAhead() != '#' || l.LookAheadNext() != '!' {
+		return
 	}
 
+	l.ConsumeComment()
 	l.start = l.current
 }
 
@@ -98,7 +193,7 @@ func (l *Lexer) ConsumeComment() {
 
 // Consume multi-line comment.
 func (l *Lexer) ConsumeMultiLineComment() {
-	for l.LookAhead() != '*' && l.LookAheadNext() != '/' && !l.IsAtEnd() {
+	for !(l.LookAhead() == '*' && l.LookAheadNext() == '/') && !l.IsAtEnd() {
 		if l.LookAhead() == '\n' {
 			l.line++
 		}
@@ -106,7 +201,7 @@ func (l *Lexer) ConsumeMultiLineComment() {
 	}
 
 	if l.IsAtEnd() {
-		e.Error(e.SyntaxError, l.line, "Unterminated multi-line comment.")
+		l.Error(l.line, "Unterminated multi-line comment.")
 		return
 	}
 
@@ -115,6 +210,15 @@ func (l *Lexer) ConsumeMultiLineComment() {
 	l.Advance()
 }
 
+// Report a syntax error on the given line.
+func (l *Lexer) Error(line int, message string) {
+	if l.reporter == nil {
+		e.Error(e.SyntaxError, line, message)
+	} else {
+		l.reporter.Error(e.SyntaxError, line, message)
+	}
+}
+
 // Return true if the lexer has reached the end of the file, false otherwise.
 func (l Lexer) IsAtEnd() bool {
 	return l.current >= len(l.source)
@@ -171,7 +275,7 @@ func (l *Lexer) AddLiteralString() {
 	}
 
 	if l.IsAtEnd() {
-		e.Error(e.SyntaxError, l.line, "Unterminated string.")
+		l.Error(l.line, "Unterminated string.")
 		return
 	}
 
@@ -181,19 +285,32 @@ func (l *Lexer) AddLiteralString() {
 	l.AddLexeme(LiteralString)
 }
 
-// Add a new LiteralNumber Lexeme to the lexer.
+// Add a new LiteralInteger Lexeme to the lexer, or a LiteralNumber Lexeme if
+// the number has a fractional part.
 func (l *Lexer) AddLiteralNumber() {
 	for IsDigit(l.LookAhead()) {
 		l.Advance()
 	}
 
-	if l.LookAhead() == '.' && IsDigit(l.LookAheadNext()) {
-		// Consume dot
-		l.Advance()
+	if !(l.LookAhead() == '.' && IsDigit(l.LookAheadNext())) {
+		l.AddLexeme(LiteralInteger)
+
+		// Verify correctness of lexeme, e.g. the integer is not out of range
+		lexeme := l.lexemes[len(l.lexemes)-1]
+		_, err := lexeme.ParseInt()
 
-		for IsDigit(l.LookAhead()) {
-			l.Advance()
+		if err != nil {
+			l.Error(lexeme.line, err.Error())
 		}
+
+		return
+	}
+
+	// Consume dot
+	l.Advance()
+
+	for IsDigit(l.LookAhead()) {
+		l.Advance()
 	}
 
 	l.AddLexeme(LiteralNumber)
@@ -203,7 +320,7 @@ func (l *Lexer) AddLiteralNumber() {
 	_, err := lexeme.ParseFloat()
 
 	if err != nil {
-		e.Error(e.SyntaxError, lexeme.line, err.Error())
+		l.Error(lexeme.line, err.Error())
 	}
 }
 
@@ -1,6 +1,8 @@
 package lexer
 
 import "fmt"
+import e "golox/errors"
+import "io/ioutil"
 import "testing"
 
 // TODO end-to-end style tests
@@ -22,16 +24,16 @@ func Test_IsAtEnd(t *testing.T) {
 
 	cases := map[*Lexer]bool {
 		// The empty string
-		{"", no_lexemes, 0, 0, 1}: true,
-		{"", no_lexemes, 0, 1, 1}: true,
-		{"", no_lexemes, 1, 1, 1}: true,
+		{"", no_lexemes, 0, 0, 1, nil}: true,
+		{"", no_lexemes, 0, 1, 1, nil}: true,
+		{"", no_lexemes, 1, 1, 1, nil}: true,
 		// Full lexeme
-		{"hello", no_lexemes, 0, 0, 1}: false,
-		{"hello", no_lexemes, 0, 1, 1}: false,
-		{"hello", no_lexemes, 0, 2, 1}: false,
-		{"hello", no_lexemes, 0, 3, 1}: false,
-		{"hello", no_lexemes, 0, 4, 1}: false,
-		{"hello", no_lexemes, 0, 5, 1}: true,
+		{"hello", no_lexemes, 0, 0, 1, nil}: false,
+		{"hello", no_lexemes, 0, 1, 1, nil}: false,
+		{"hello", no_lexemes, 0, 2, 1, nil}: false,
+		{"hello", no_lexemes, 0, 3, 1, nil}: false,
+		{"hello", no_lexemes, 0, 4, 1, nil}: false,
+		{"hello", no_lexemes, 0, 5, 1, nil}: true,
 	}
 
 	for l, expected := range cases {
@@ -53,11 +55,11 @@ func Test_Advance(t *testing.T) {
 
 	cases := map[*Lexer]byte {
 		// Full lexeme
-		{"hello", no_lexemes, 0, 0, 1}: 'h',
-		{"hello", no_lexemes, 0, 1, 1}: 'e',
-		{"hello", no_lexemes, 0, 2, 1}: 'l',
-		{"hello", no_lexemes, 0, 3, 1}: 'l',
-		{"hello", no_lexemes, 0, 4, 1}: 'o',
+		{"hello", no_lexemes, 0, 0, 1, nil}: 'h',
+		{"hello", no_lexemes, 0, 1, 1, nil}: 'e',
+		{"hello", no_lexemes, 0, 2, 1, nil}: 'l',
+		{"hello", no_lexemes, 0, 3, 1, nil}: 'l',
+		{"hello", no_lexemes, 0, 4, 1, nil}: 'o',
 	}
 
 	for l, expected := range cases {
@@ -92,16 +94,16 @@ func Test_LookAhead(t *testing.T) {
 
 	cases := map[*Lexer]byte {
 		// The empty string
-		{"", no_lexemes, 0, 0, 1}: 0,
-		{"", no_lexemes, 0, 1, 1}: 0,
-		{"", no_lexemes, 1, 1, 1}: 0,
+		{"", no_lexemes, 0, 0, 1, nil}: 0,
+		{"", no_lexemes, 0, 1, 1, nil}: 0,
+		{"", no_lexemes, 1, 1, 1, nil}: 0,
 		// Full lexeme
-		{"hello", no_lexemes, 0, 0, 1}: 'h',
-		{"hello", no_lexemes, 0, 1, 1}: 'e',
-		{"hello", no_lexemes, 0, 2, 1}: 'l',
-		{"hello", no_lexemes, 0, 3, 1}: 'l',
-		{"hello", no_lexemes, 0, 4, 1}: 'o',
-		{"hello", no_lexemes, 0, 5, 1}: 0,
+		{"hello", no_lexemes, 0, 0, 1, nil}: 'h',
+		{"hello", no_lexemes, 0, 1, 1, nil}: 'e',
+		{"hello", no_lexemes, 0, 2, 1, nil}: 'l',
+		{"hello", no_lexemes, 0, 3, 1, nil}: 'l',
+		{"hello", no_lexemes, 0, 4, 1, nil}: 'o',
+		{"hello", no_lexemes, 0, 5, 1, nil}: 0,
 	}
 
 	for l, expected := range cases {
@@ -123,15 +125,15 @@ func Test_LookAheadNext(t *testing.T) {
 
 	cases := map[*Lexer]byte {
 		// The empty string
-		{"", no_lexemes, 0, 0, 1}: 0,
-		{"", no_lexemes, 0, 1, 1}: 0,
-		{"", no_lexemes, 1, 1, 1}: 0,
+		{"", no_lexemes, 0, 0, 1, nil}: 0,
+		{"", no_lexemes, 0, 1, 1, nil}: 0,
+		{"", no_lexemes, 1, 1, 1, nil}: 0,
 		// Full lexeme
-		{"hello", no_lexemes, 0, 0, 1}: 'e',
-		{"hello", no_lexemes, 0, 1, 1}: 'l',
-		{"hello", no_lexemes, 0, 2, 1}: 'l',
-		{"hello", no_lexemes, 0, 3, 1}: 'o',
-		{"hello", no_lexemes, 0, 4, 1}: 0,
+		{"hello", no_lexemes, 0, 0, 1, nil}: 'e',
+		{"hello", no_lexemes, 0, 1, 1, nil}: 'l',
+		{"hello", no_lexemes, 0, 2, 1, nil}: 'l',
+		{"hello", no_lexemes, 0, 3, 1, nil}: 'o',
+		{"hello", no_lexemes, 0, 4, 1, nil}: 0,
 	}
 
 	for l, expected := range cases {
@@ -154,11 +156,11 @@ func Test_Match(t *testing.T) {
 
 	// Neative cases -- at end
 	negative_cases := []Lexer{
-		{"", no_lexemes, 0, 0, 1},
-		{"", no_lexemes, 0, 0, 1},
-		{"if", no_lexemes, 0, 2, 1},
-		{"if", no_lexemes, 1, 2, 1},
-		{"if", no_lexemes, 2, 2, 1},
+		{"", no_lexemes, 0, 0, 1, nil},
+		{"", no_lexemes, 0, 0, 1, nil},
+		{"if", no_lexemes, 0, 2, 1, nil},
+		{"if", no_lexemes, 1, 2, 1, nil},
+		{"if", no_lexemes, 2, 2, 1, nil},
 	}
 
 	for _, lexer := range negative_cases {
@@ -179,28 +181,28 @@ func Test_Match(t *testing.T) {
 
 	positive_cases := map[*Lexer]byte{
 		// Simple -- keyword
-		{"if", no_lexemes, 0, 1, 1}:  'f',
-		{"if", no_lexemes, 1, 1, 1}:  'f',
-		{"if", no_lexemes, 99, 1, 1}: 'f',
+		{"if", no_lexemes, 0, 1, 1, nil}:  'f',
+		{"if", no_lexemes, 1, 1, 1, nil}:  'f',
+		{"if", no_lexemes, 99, 1, 1, nil}: 'f',
 		// Complex -- keyword
-		{complex_source, no_lexemes, 0, 0, 1}: 'i',
-		{complex_source, no_lexemes, 0, 1, 1}: 'f',
+		{complex_source, no_lexemes, 0, 0, 1, nil}: 'i',
+		{complex_source, no_lexemes, 0, 1, 1, nil}: 'f',
 		// Complex -- identifier
-		{complex_source, no_lexemes, 3, 3, 1}: 'f',
-		{complex_source, no_lexemes, 3, 4, 1}: 'o',
-		{complex_source, no_lexemes, 3, 5, 1}: 'o',
+		{complex_source, no_lexemes, 3, 3, 1, nil}: 'f',
+		{complex_source, no_lexemes, 3, 4, 1, nil}: 'o',
+		{complex_source, no_lexemes, 3, 5, 1, nil}: 'o',
 		// Complex -- match EqualEqual
-		{complex_source, no_lexemes, 7, 7, 1}: '=',
-		{complex_source, no_lexemes, 7, 8, 1}: '=',
+		{complex_source, no_lexemes, 7, 7, 1, nil}: '=',
+		{complex_source, no_lexemes, 7, 8, 1, nil}: '=',
 		// Complex -- literal
-		{complex_source, no_lexemes, 10, 10, 1}: '4',
+		{complex_source, no_lexemes, 10, 10, 1, nil}: '4',
 		// Complex -- closing }
-		{complex_source, no_lexemes, 25, 25, 1}: '}',
+		{complex_source, no_lexemes, 25, 25, 1, nil}: '}',
 		// Complex -- whitespace
-		{complex_source, no_lexemes, 2, 2, 1}:   ' ',
-		{complex_source, no_lexemes, 6, 6, 1}:   ' ',
-		{complex_source, no_lexemes, 12, 12, 1}: '\n',
-		{complex_source, no_lexemes, 13, 13, 1}: '\t',
+		{complex_source, no_lexemes, 2, 2, 1, nil}:   ' ',
+		{complex_source, no_lexemes, 6, 6, 1, nil}:   ' ',
+		{complex_source, no_lexemes, 12, 12, 1, nil}: '\n',
+		{complex_source, no_lexemes, 13, 13, 1, nil}: '\t',
 	}
 
 	for l, expected := range positive_cases {
@@ -303,3 +305,316 @@ func Test_IsAlphanumeric(t *testing.T) {
 		}
 	}
 }
+
+func Test_ConsumeShebang(t *testing.T) {
+	f := "Lexer{'%s', start=%d, current=%d}"
+	no_lexemes := make([]Lexeme, 0)
+
+	cases := map[*Lexer]int{
+		// No shebang
+		{"", no_lexemes, 0, 0, 1, nil}:                     0,
+		{"print 1;", no_lexemes, 0, 0, 1, nil}:             0,
+		{"# comment", no_lexemes, 0, 0, 1, nil}:            0,
+		{"!#/usr/bin/env golox", no_lexemes, 0, 0, 1, nil}: 0,
+		// Not at the start of the source
+		{"\n#!/usr/bin/env golox", no_lexemes, 1, 1, 1, nil}: 1,
+		// Shebang
+		{"#!", no_lexemes, 0, 0, 1, nil}:                             2,
+		{"#!/usr/bin/env golox", no_lexemes, 0, 0, 1, nil}:           20,
+		{"#!/usr/bin/env golox\nprint 1;", no_lexemes, 0, 0, 1, nil}: 20,
+	}
+
+	for l, expected := range cases {
+		previous := l.current
+		l.ConsumeShebang()
+
+		if l.current != expected || l.start != l.current {
+			t.Logf(
+				"%s.ConsumeShebang() expects start=current=%d received start=%d, current=%d",
+				fmt.Sprintf(f, l.source, l.start, previous),
+				expected,
+				l.start,
+				l.current,
+			)
+			t.Fail()
+		}
+	}
+
+	// The shebang line is not lexed
+	lexemes := Lex("#!/usr/bin/env golox\nprint;")
+
+	if len(lexemes) != 2 || lexemes[0].lexeme != "print" || lexemes[0].line != 2 {
+		t.Logf("Lex() expects the shebang line to be skipped, received %s", lexemes)
+		t.Fail()
+	}
+}
+
+func Test_ConsumeMultiLineComment(t *testing.T) {
+	cases := map[string]int{
+		"/**/":                  0,
+		"/* comment */":         0,
+		"/* a * b / c */":       0,
+		"/* one\ntwo */ print":  1,
+		"/* a */ print /* b */": 1,
+	}
+
+	for source, expected := range cases {
+		lexemes := Lex(source)
+
+		if len(lexemes) != expected {
+			t.Logf("Lex(%q) expects %d lexemes received %s", source, expected, lexemes)
+			t.Fail()
+		}
+	}
+}
+
+func Test_IsComplete(t *testing.T) {
+	cases := map[string]bool{
+		// Complete
+		"":                         true,
+		"print 1;":                 true,
+		"fun f() { print 1; }":     true,
+		"print (1 + (2 * 3));":     true,
+		"print \"{\";":             true,
+		"print 1; // {":            true,
+		"/* ( */ print 1;":         true,
+		"print 1; }":               true,
+		"#!/usr/bin/env golox\n{}": true,
+		"print @;":                 true,
+		"var xs = [1, [2]];":       true,
+		// Unclosed parentheses or braces
+		"fun f() {":              false,
+		"fun f() {\n if (a) {\n": false,
+		"fun f() {\n}\n{":        false,
+		"print (1 + (2 * 3);":    false,
+		"print (":                false,
+		"var xs = [1,\n2,":       false,
+		"print xs[1":             false,
+		"} {":                    false,
+		"print 1; )\n(":          false,
+		// Unterminated strings or multi-line comments
+		"print \"hello":          false,
+		"print \"hello\nworld":   false,
+		"/* comment":             false,
+		"/* comment *":           false,
+		"print 1; /* a * b / c ": false,
+	}
+
+	for source, expected := range cases {
+		if IsComplete(source) != expected {
+			t.Logf("IsComplete(%q) expects %t", source, expected)
+			t.Fail()
+		}
+	}
+}
+
+func Test_AddIdentifier(t *testing.T) {
+	cases := map[string]LexemeType{
+		// Keywords
+		"and":    And,
+		"class":  Class,
+		"else":   Else,
+		"false":  False,
+		"for":    For,
+		"fun":    Fun,
+		"if":     If,
+		"nil":    Nil,
+		"or":     Or,
+		"print":  Print,
+		"return": Return,
+		"super":  Super,
+		"this":   This,
+		"true":   True,
+		"var":    Var,
+		"while":  While,
+		// Keywords for loops
+		"break":    Break,
+		"continue": Continue,
+		// Keywords for exceptions
+		"try":     Try,
+		"catch":   Catch,
+		"finally": Finally,
+		"throw":   Throw,
+		// Identifiers
+		"foo":    Identifier,
+		"classy": Identifier,
+		"i_f":    Identifier,
+		"print2": Identifier,
+		"and_or": Identifier,
+	}
+
+	for source, expected := range cases {
+		lexemes := Lex(source)
+
+		if len(lexemes) != 1 || lexemes[0].lexeme_type != expected {
+			t.Logf("Lex(%q) expects a single %s received %s", source, expected, lexemes)
+			t.Fail()
+		}
+	}
+
+	// Every keyword is lexed as such
+	for _, keyword := range Keywords() {
+		if lexemes := Lex(keyword); lexemes[0].lexeme_type == Identifier {
+			t.Logf("Lex(%q) expects a keyword", keyword)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Lex_Concurrent(t *testing.T) {
+	// The lines of the errors each source is expected to report
+	sources := map[string][]int{
+		"print 1;":       {},
+		"var a = \"b\";": {},
+		"class a {}":     {},
+		"print @;":       {1},
+	}
+	done := make(chan bool)
+	had_error := e.HasHadError()
+
+	for i := 0; i < 100; i++ {
+		for source, lines := range sources {
+			go func(source string, lines []int) {
+				reporter := e.NewReporter(ioutil.Discard)
+
+				LexWith(source, reporter)
+				errors := reporter.Errors()
+
+				if len(errors) != len(lines) {
+					t.Logf("LexWith(%q) expects %d errors received %v", source, len(lines), errors)
+					t.Fail()
+				}
+
+				for j := 0; j < len(errors) && j < len(lines); j++ {
+					if errors[j].Line != lines[j] {
+						t.Logf("LexWith(%q) expects an error on line %d received %v", source, lines[j], errors[j])
+						t.Fail()
+					}
+				}
+
+				done <- true
+			}(source, lines)
+		}
+	}
+
+	for i := 0; i < 100*len(sources); i++ {
+		<-done
+	}
+
+	// assert the global error state is untouched
+	if e.HasHadError() != had_error {
+		t.Fatalf("expect LexWith not to change the global error state")
+	}
+}
+
+func Test_ConsumeLexeme(t *testing.T) {
+	cases := map[string]LexemeType{
+		// Single-character lexemes
+		"(": LeftParenthesis,
+		")": RightParenthesis,
+		"{": LeftBrace,
+		"}": RightBrace,
+		"[": LeftBracket,
+		"]": RightBracket,
+		",": Comma,
+		".": Dot,
+		"-": Minus,
+		"+": Plus,
+		";": Semicolon,
+		"/": Slash,
+		"*": Star,
+		"%": Percent,
+		"?": Question,
+		":": Colon,
+		// One-or-two character lexemes
+		"!":  Bang,
+		"!=": BangEqual,
+		"=":  Equal,
+		"==": EqualEqual,
+		">":  Greater,
+		">=": GreaterEqual,
+		"<":  Less,
+		"<=": LessEqual,
+		"=>": Arrow,
+		"~/": TildeSlash,
+	}
+
+	for source, expected := range cases {
+		lexemes := Lex(source)
+
+		if len(lexemes) != 1 || lexemes[0].lexeme_type != expected || lexemes[0].lexeme != source {
+			t.Logf("Lex(%q) expects a single %s received %s", source, expected, lexemes)
+			t.Fail()
+		}
+	}
+
+	// Sequences of lexemes
+	sequences := map[string][]LexemeType{
+		"a ? b : c":   {Identifier, Question, Identifier, Colon, Identifier},
+		"(a) => a":    {LeftParenthesis, Identifier, RightParenthesis, Arrow, Identifier},
+		"a ==> b":     {Identifier, EqualEqual, Greater, Identifier},
+		"a = > b":     {Identifier, Equal, Greater, Identifier},
+		"a => = b":    {Identifier, Arrow, Equal, Identifier},
+		"fun (a) { }": {Fun, LeftParenthesis, Identifier, RightParenthesis, LeftBrace, RightBrace},
+		"[1][0]":      {LeftBracket, LiteralInteger, RightBracket, LeftBracket, LiteralInteger, RightBracket},
+		"3 % 2":       {LiteralInteger, Percent, LiteralInteger},
+		"7 ~/ 2":      {LiteralInteger, TildeSlash, LiteralInteger},
+		"7 ~// 2":     {LiteralInteger, TildeSlash, Slash, LiteralInteger},
+	}
+
+	for source, expected := range sequences {
+		lexemes := Lex(source)
+
+		if len(lexemes) != len(expected) {
+			t.Logf("Lex(%q) expects %d lexemes received %s", source, len(expected), lexemes)
+			t.Fail()
+			continue
+		}
+
+		for i, lexeme := range lexemes {
+			if lexeme.lexeme_type != expected[i] {
+				t.Logf("Lex(%q) expects %s at %d received %s", source, expected[i], i, lexeme)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func Test_AddLiteralNumber(t *testing.T) {
+	cases := map[string]LexemeType{
+		// Integers
+		"0":                   LiteralInteger,
+		"3":                   LiteralInteger,
+		"003":                 LiteralInteger,
+		"9223372036854775807": LiteralInteger,
+		// Numbers with a fractional part
+		"3.0":   LiteralNumber,
+		"0.5":   LiteralNumber,
+		"12.34": LiteralNumber,
+	}
+
+	for source, expected := range cases {
+		reporter := e.NewReporter(ioutil.Discard)
+		lexemes := LexWith(source, reporter)
+
+		if len(lexemes) != 1 || lexemes[0].lexeme_type != expected || reporter.HasHadError() {
+			t.Logf("Lex(%q) expects a single %s received %s", source, expected, lexemes)
+			t.Fail()
+		}
+	}
+
+	// A trailing dot is not part of the number
+	if lexemes := Lex("3."); len(lexemes) != 2 || lexemes[0].lexeme_type != LiteralInteger {
+		t.Logf("Lex(\"3.\") expects LiteralInteger and Dot received %s", lexemes)
+		t.Fail()
+	}
+
+	// Integers out of range are errors
+	reporter := e.NewReporter(ioutil.Discard)
+	LexWith("9223372036854775808", reporter)
+
+	if !reporter.HasHadError() {
+		t.Logf("Lex(\"9223372036854775808\") expects an out of range error")
+		t.Fail()
+	}
+}
@@ -0,0 +1,110 @@
+package repl
+
+import "fmt"
+import "io/ioutil"
+import "strings"
+import "time"
+
+// Command is a REPL meta-command, entered as ":name argument".
+type Command struct {
+	name        string
+	argument    string
+	description string
+	run         func(r *Repl, argument string)
+}
+
+// The REPL meta-commands, set in init as :help refers to them.
+var commands []Command
+
+func init() {
+	commands = []Command{
+		{"tokens", "<source>", "print the lexemes of the source", Tokens},
+		{"ast", "<source>", "print the syntax tree of the source", Unavailable},
+		{"env", "", "print the global variables and their values", Unavailable},
+		{"load", "<file>", "run the source in the file", Load},
+		{"reset", "", "start a new session", Reset},
+		{"time", "<source>", "run the source and print the time taken", Time},
+		{"help", "", "print this help", Help},
+		{"quit", "", "end the session", Quit},
+	}
+}
+
+// Return true if the given line is a meta-command.
+func IsCommand(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), ":")
+}
+
+// RunCommand runs the meta-command on the given line.
+func (r *Repl) RunCommand(line string) {
+	name := strings.TrimPrefix(strings.TrimSpace(line), ":")
+	argument := ""
+
+	if i := strings.IndexAny(name, " \t"); i != -1 {
+		name, argument = name[:i], strings.TrimSpace(name[i:])
+	}
+
+	for _, command := range commands {
+		if command.name == name {
+			command.run(r, argument)
+			return
+		}
+	}
+
+	fmt.Fprintf(r.error_writer, "Unknown command ':%s', see :help\n", name)
+}
+
+// Tokens prints the lexemes of the given source.
+func Tokens(r *Repl, source string) {
+	for _, lexeme := range r.session.Lex(source) {
+		fmt.Fprintln(r.writer, lexeme)
+	}
+}
+
+// Unavailable reports that the command is not yet implemented.
+func Unavailable(r *Repl, argument string) {
+	fmt.Fprintln(r.error_writer, "Not yet implemented")
+}
+
+// Load runs the source in the given file, unless the session is remote.
+func Load(r *Repl, path string) {
+	if r.remote {
+		fmt.Fprintln(r.error_writer, ":load is disabled for network sessions")
+		return
+	}
+
+	source, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		fmt.Fprintln(r.error_writer, err)
+		return
+	}
+
+	r.session.Run(string(source))
+}
+
+// Reset starts a new session.
+func Reset(r *Repl, argument string) {
+	r.session = NewInterpreter(r.reader, r.writer, r.error_writer)
+}
+
+// Time runs the given source and prints the time taken.
+func Time(r *Repl, source string) {
+	start := time.Now()
+
+	r.session.Run(source)
+
+	fmt.Fprintf(r.writer, "Took %s\n", time.Since(start))
+}
+
+// Help prints the meta-commands.
+func Help(r *Repl, argument string) {
+	for _, command := range commands {
+		usage := strings.TrimSpace(":" + command.name + " " + command.argument)
+		fmt.Fprintf(r.writer, "  %-18s %s\n", usage, command.description)
+	}
+}
+
+// Quit ends the session.
+func Quit(r *Repl, argument string) {
+	r.quit = true
+}
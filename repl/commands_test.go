@@ -0,0 +1,93 @@
+package repl
+
+import "bytes"
+import "io/ioutil"
+import "path/filepath"
+import "strings"
+import "testing"
+
+func Test_IsCommand(t *testing.T) {
+	cases := map[string]bool{
+		":help":        true,
+		"  :quit":      true,
+		":tokens 1;":   true,
+		"":             false,
+		"print 1;":     false,
+		"print \":\";": false,
+	}
+
+	for line, expected := range cases {
+		if IsCommand(line) != expected {
+			t.Logf("IsCommand(%q) expects %t", line, expected)
+			t.Fail()
+		}
+	}
+}
+
+func Test_RunCommand(t *testing.T) {
+	session := NewInterpreter(strings.NewReader(""), ioutil.Discard, ioutil.Discard)
+	repl := Repl{session: session, writer: ioutil.Discard, error_writer: ioutil.Discard}
+
+	repl.RunCommand(":reset")
+
+	if repl.session == session {
+		t.Fatalf(":reset expects a new session")
+	}
+
+	repl.RunCommand(":unknown")
+	repl.RunCommand(":quitting")
+
+	if repl.quit {
+		t.Fatalf(":quitting expects not to quit")
+	}
+
+	repl.RunCommand(" :quit ")
+
+	if !repl.quit {
+		t.Fatalf(":quit expects to quit")
+	}
+}
+
+func Test_RunCommand_Output(t *testing.T) {
+	directory := t.TempDir()
+	path := filepath.Join(directory, "script.lox")
+	missing := filepath.Join(directory, "missing.lox")
+
+	if err := ioutil.WriteFile(path, []byte("print 2;"), 0600); err != nil {
+		t.Fatalf("error writing script: %s", err)
+	}
+
+	help := ""
+	for _, command := range commands {
+		help += ":" + command.name + "\n"
+	}
+
+	// Each line of the expected output is expected somewhere in the output
+	cases := map[string]string{
+		":tokens 1;":       "Lexeme(type=LiteralInteger, lexeme=\"1\", literal=1)",
+		":help":            help,
+		":load " + path:    "Lexeme(type=LiteralInteger, lexeme=\"2\", literal=2)",
+		":load " + missing: "open " + missing,
+		":time 1;":         "Took ",
+		":x":               "Unknown command ':x', see :help",
+		":ast 1;":          "Not yet implemented",
+	}
+
+	for line, expected := range cases {
+		var output bytes.Buffer
+		repl := Repl{
+			session:      NewInterpreter(strings.NewReader(""), &output, &output),
+			writer:       &output,
+			error_writer: &output,
+		}
+
+		repl.RunCommand(line)
+
+		for _, part := range strings.Split(strings.TrimSpace(expected), "\n") {
+			if !strings.Contains(output.String(), part) {
+				t.Logf("RunCommand(%q) expects %q in the output, received %q", line, part, output.String())
+				t.Fail()
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package repl
+
+import "golox/lexer"
+import "strings"
+
+// Complete returns the completions of the given word: the meta-commands after
+// a ":" at the start of the line, the keywords otherwise.
+func (r *Repl) Complete(before string, word string) []string {
+	candidates := []string{}
+
+	if strings.TrimSpace(before) == ":" {
+		for _, command := range commands {
+			candidates = append(candidates, command.name)
+		}
+	} else if !strings.HasSuffix(strings.TrimSpace(before), ".") {
+		candidates = lexer.Keywords()
+	}
+
+	completions := []string{}
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			completions = append(completions, candidate)
+		}
+	}
+
+	return completions
+}
@@ -0,0 +1,38 @@
+package repl
+
+import "io/ioutil"
+import "reflect"
+import "strings"
+import "testing"
+
+func Test_Complete(t *testing.T) {
+	repl := Repl{
+		session:      NewInterpreter(strings.NewReader(""), ioutil.Discard, ioutil.Discard),
+		writer:       ioutil.Discard,
+		error_writer: ioutil.Discard,
+	}
+
+	cases := map[[2]string][]string{
+		// Keywords
+		{"", "wh"}:         {"while"},
+		{"", "c"}:          {"catch", "class", "continue"},
+		{"", "f"}:          {"false", "finally", "for", "fun"},
+		{"var x = ", "tr"}: {"true", "try"},
+		{"", "foo"}:        {},
+		// Meta-commands
+		{":", "t"}:   {"tokens", "time"},
+		{" : ", "q"}: {"quit"},
+		{":", "x"}:   {},
+		// Members
+		{"a.", "f"}: {},
+	}
+
+	for input, expected := range cases {
+		completions := repl.Complete(input[0], input[1])
+
+		if !reflect.DeepEqual(completions, expected) {
+			t.Logf("Complete(%q, %q) expects %q received %q", input[0], input[1], expected, completions)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package repl
+
+import "bufio"
+import "errors"
+import "fmt"
+import "golox/lexer"
+import "io"
+import "os"
+import "strings"
+
+// ErrInterrupted is returned when Ctrl-C is pressed while editing a line.
+var ErrInterrupted = errors.New("interrupted")
+
+// Keys which are not runes, decoded from terminal escape sequences.
+const (
+	KeyUnknown rune = -(iota + 1)
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyDelete
+)
+
+// Return the rune sent by the terminal for the given control key.
+func Control(r rune) rune {
+	return r & 0x1f
+}
+
+const (
+	Escape    rune = 0x1b
+	Backspace rune = 0x7f
+)
+
+// Completer returns the completions of the word before the cursor, given the
+// line before the word.
+type Completer func(before string, word string) []string
+
+// Editor reads lines from a terminal with cursor motion, history, reverse
+// history search, and tab completion.
+type Editor struct {
+	input     io.Reader
+	reader    *bufio.Reader
+	writer    io.Writer
+	history   *History
+	completer Completer
+
+	// The line being edited and the cursor position within it
+	line   []rune
+	cursor int
+
+	// The index of the history entry being edited, and the edited line before
+	// moving through the history
+	history_index int
+	pending       []rune
+}
+
+// NewEditor returns an editor reading from the given input and echoing to the
+// given writer, adding each line read to the given history.
+func NewEditor(input io.Reader, writer io.Writer, history *History) *Editor {
+	return &Editor{
+		input:   input,
+		reader:  bufio.NewReader(input),
+		writer:  writer,
+		history: history,
+	}
+}
+
+// ReadLine prints the prompt and returns the line entered without the trailing
+// newline.
+// If the input is a terminal it is put into raw mode for editing the line,
+// otherwise the line is read as is.
+// io.EOF is returned if the input ends, or Ctrl-D is pressed, on an empty line,
+// and ErrInterrupted if Ctrl-C is pressed.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	file, is_file := e.input.(*os.File)
+
+	if !is_file {
+		return e.ReadPlainLine(prompt)
+	}
+
+	restore, err := MakeRaw(int(file.Fd()))
+
+	if err != nil {
+		return e.ReadPlainLine(prompt)
+	}
+
+	defer restore()
+
+	return e.EditLine(prompt)
+}
+
+// ReadPlainLine prints the prompt and returns the next line of input without
+// editing it.
+// The line is not added to the history, as it was not entered at a terminal.
+func (e *Editor) ReadPlainLine(prompt string) (string, error) {
+	fmt.Fprint(e.writer, prompt)
+
+	line, err := e.reader.ReadString('\n')
+
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+// EditLine prints the prompt and returns the line edited by the key presses
+// read from the input, which is expected to be a terminal in raw mode.
+func (e *Editor) EditLine(prompt string) (string, error) {
+	e.line = e.line[:0]
+	e.cursor = 0
+	e.history_index = e.history.Len()
+	e.pending = nil
+
+	e.Refresh(prompt)
+
+	for true {
+		key, err := e.ReadKey()
+
+		if err == io.EOF && len(e.line) != 0 {
+			key = '\r'
+		} else if err != nil {
+			return "", err
+		}
+
+		if key == Control('r') {
+			key, err = e.Search()
+
+			if err != nil {
+				return "", err
+			}
+		}
+
+		switch key {
+		case '\r', '\n':
+			fmt.Fprint(e.writer, "\r\n")
+			line := string(e.line)
+			e.history.Add(line)
+			return line, nil
+		case Control('c'):
+			fmt.Fprint(e.writer, "^C\r\n")
+			return "", ErrInterrupted
+		case Control('d'):
+			if len(e.line) == 0 {
+				fmt.Fprint(e.writer, "\r\n")
+				return "", io.EOF
+			}
+			e.Delete()
+		case Backspace, Control('h'):
+			if e.cursor > 0 {
+				e.cursor--
+				e.Delete()
+			}
+		case KeyDelete:
+			e.Delete()
+		case KeyLeft, Control('b'):
+			if e.cursor > 0 {
+				e.cursor--
+			}
+		case KeyRight, Control('f'):
+			if e.cursor < len(e.line) {
+				e.cursor++
+			}
+		case KeyHome, Control('a'):
+			e.cursor = 0
+		case KeyEnd, Control('e'):
+			e.cursor = len(e.line)
+		case Control('k'):
+			e.line = e.line[:e.cursor]
+		case Control('u'):
+			e.line = append(e.line[:0], e.line[e.cursor:]...)
+			e.cursor = 0
+		case '\t':
+			e.Complete()
+		case KeyUp, Control('p'):
+			e.MoveThroughHistory(-1)
+		case KeyDown, Control('n'):
+			e.MoveThroughHistory(1)
+		default:
+			if key >= ' ' {
+				e.Insert(key)
+			}
+		}
+
+		e.Refresh(prompt)
+	}
+
+	return "", nil
+}
+
+// Search the history backwards for lines containing the query typed, as per
+// Ctrl-R in readline.
+// The line is set to the match when the search ends, and the key which ended
+// the search is returned to be handled by the editor. Ctrl-G cancels the
+// search, keeping the line as it was.
+func (e *Editor) Search() (rune, error) {
+	original := append([]rune{}, e.line...)
+	original_cursor := e.cursor
+
+	query := []rune{}
+	index := e.history.Len()
+
+	for true {
+		match := ""
+		if index >= 0 && index < e.history.Len() {
+			match = e.history.At(index)
+		}
+
+		fmt.Fprintf(e.writer, "\r(reverse-i-search)`%s': %s\x1b[K", string(query), match)
+
+		key, err := e.ReadKey()
+
+		if err != nil {
+			return 0, err
+		}
+
+		switch key {
+		case Control('r'):
+			if older := e.history.Search(string(query), index-1); older != -1 {
+				index = older
+			}
+		case Backspace, Control('h'):
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				index = e.history.Search(string(query), e.history.Len()-1)
+			}
+		case Control('g'):
+			e.line = original
+			e.cursor = original_cursor
+			return KeyUnknown, nil
+		default:
+			if key >= ' ' {
+				query = append(query, key)
+				index = e.history.Search(string(query), index)
+				continue
+			}
+
+			if index >= 0 && index < e.history.Len() {
+				e.SetLine([]rune(match))
+				e.history_index = index
+			}
+
+			return key, nil
+		}
+	}
+
+	return KeyUnknown, nil
+}
+
+// ReadKey returns the next key pressed, decoding escape sequences for the arrow
+// keys, Home, End, and Delete.
+func (e *Editor) ReadKey() (rune, error) {
+	r, _, err := e.reader.ReadRune()
+
+	if err != nil || r != Escape {
+		return r, err
+	}
+
+	next, _, err := e.reader.ReadRune()
+
+	if err != nil {
+		return KeyUnknown, err
+	}
+
+	switch next {
+	case 'O':
+		final, _, err := e.reader.ReadRune()
+		return DecodeEscape("", final), err
+	case '[':
+		parameters := ""
+		final, _, err := e.reader.ReadRune()
+
+		for err == nil && (('0' <= final && final <= '9') || final == ';') {
+			parameters += string(final)
+			final, _, err = e.reader.ReadRune()
+		}
+
+		return DecodeEscape(parameters, final), err
+	default:
+		return KeyUnknown, nil
+	}
+}
+
+// Return the key for the escape sequence with the given parameters and final
+// character.
+func DecodeEscape(parameters string, final rune) rune {
+	switch final {
+	case 'A':
+		return KeyUp
+	case 'B':
+		return KeyDown
+	case 'C':
+		return KeyRight
+	case 'D':
+		return KeyLeft
+	case 'H':
+		return KeyHome
+	case 'F':
+		return KeyEnd
+	case '~':
+		switch parameters {
+		case "1", "7":
+			return KeyHome
+		case "4", "8":
+			return KeyEnd
+		case "3":
+			return KeyDelete
+		}
+	}
+
+	return KeyUnknown
+}
+
+// Complete the word before the cursor with the longest prefix common to its
+// completions, listing the completions if they are ambiguous.
+func (e *Editor) Complete() {
+	if e.completer == nil {
+		return
+	}
+
+	start := e.cursor
+	for start > 0 && IsWordRune(e.line[start-1]) {
+		start--
+	}
+
+	word := string(e.line[start:e.cursor])
+	completions := []string{}
+
+	for _, completion := range e.completer(string(e.line[:start]), word) {
+		if strings.HasPrefix(completion, word) {
+			completions = append(completions, completion)
+		}
+	}
+
+	if len(completions) == 0 {
+		return
+	}
+
+	prefix := CommonPrefix(completions)
+
+	for _, r := range prefix[len(word):] {
+		e.Insert(r)
+	}
+
+	if len(completions) > 1 && prefix == word {
+		fmt.Fprintf(e.writer, "\r\n%s\r\n", strings.Join(completions, "  "))
+	}
+}
+
+// Return true if the given rune may be part of a word to complete.
+func IsWordRune(r rune) bool {
+	return r < 0x80 && lexer.IsAlphanumeric(byte(r))
+}
+
+// Return the longest prefix common to the given strings.
+func CommonPrefix(words []string) string {
+	prefix := words[0]
+
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
+
+// Insert the given rune at the cursor.
+func (e *Editor) Insert(r rune) {
+	e.line = append(e.line, 0)
+	copy(e.line[e.cursor+1:], e.line[e.cursor:])
+	e.line[e.cursor] = r
+	e.cursor++
+}
+
+// Delete the rune at the cursor.
+func (e *Editor) Delete() {
+	if e.cursor < len(e.line) {
+		e.line = append(e.line[:e.cursor], e.line[e.cursor+1:]...)
+	}
+}
+
+// Replace the line, moving the cursor to the end.
+func (e *Editor) SetLine(line []rune) {
+	e.line = append(e.line[:0], line...)
+	e.cursor = len(e.line)
+}
+
+// Replace the line with the history entry the given offset from the current
+// entry, restoring the edited line when moving past the newest entry.
+func (e *Editor) MoveThroughHistory(offset int) {
+	index := e.history_index + offset
+
+	if index < 0 || index > e.history.Len() {
+		return
+	}
+
+	if e.history_index == e.history.Len() {
+		e.pending = append([]rune{}, e.line...)
+	}
+
+	if index == e.history.Len() {
+		e.SetLine(e.pending)
+	} else {
+		e.SetLine([]rune(e.history.At(index)))
+	}
+
+	e.history_index = index
+}
+
+// Redraw the prompt and line, and place the cursor.
+func (e *Editor) Refresh(prompt string) {
+	fmt.Fprintf(e.writer, "\r%s%s\x1b[K\r", prompt, string(e.line))
+
+	if column := len([]rune(prompt)) + e.cursor; column > 0 {
+		fmt.Fprintf(e.writer, "\x1b[%dC", column)
+	}
+}
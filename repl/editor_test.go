@@ -0,0 +1,220 @@
+package repl
+
+import "io"
+import "io/ioutil"
+import "strings"
+import "testing"
+
+// Return an editor reading the given key presses with the given history.
+func EditorFor(keys string, lines ...string) *Editor {
+	history := LoadHistory("")
+
+	for _, line := range lines {
+		history.Add(line)
+	}
+
+	return NewEditor(strings.NewReader(keys), ioutil.Discard, history)
+}
+
+func Test_EditLine(t *testing.T) {
+	cases := map[string]string{
+		// Plain lines
+		"\r":             "",
+		"print 1;\r":     "print 1;",
+		"print 1;\n":     "print 1;",
+		"print 1;":       "print 1;",
+		"print \"é\";\r": "print \"é\";",
+		// Backspace and delete
+		"printt\x7f 1;\r":          "print 1;",
+		"print 1;;\x1b[D\x1b[3~\r": "print 1;",
+		"print 1;;\x02\x04\r":      "print 1;",
+		// Cursor motion
+		"rint 1;\x01p\r":       "print 1;",
+		"rint 1;\x1b[Hp\r":     "print 1;",
+		"rint 1;\x1bOHp\r":     "print 1;",
+		"print 1\x01\x05;\r":   "print 1;",
+		"print 1\x01\x1b[F;\r": "print 1;",
+		"print;\x1b[D 1\r":     "print 1;",
+		"print;\x02 1\r":       "print 1;",
+		"prit 1;\x1b[D\x1b[D\x1b[D\x1b[D\x1b[D\x1b[Cn\r": "print 1;",
+		"prit 1;\x02\x02\x02\x02\x02\x06n\r":             "print 1;",
+		// Killing
+		"print 1; 2;\x02\x02\x02\x0b\r":                           "print 1;",
+		"print 2; print 1;\x02\x02\x02\x02\x02\x02\x02\x02\x15\r": "print 1;",
+		// Unknown escape sequences are ignored
+		"print 1;\x1b[99X\r": "print 1;",
+	}
+
+	for keys, expected := range cases {
+		line, err := EditorFor(keys).EditLine("> ")
+
+		if err != nil || line != expected {
+			t.Logf(
+				"EditLine() of %q expects %q received %q, %v",
+				keys,
+				expected,
+				line,
+				err,
+			)
+			t.Fail()
+		}
+	}
+}
+
+func Test_EditLine_EOF(t *testing.T) {
+	cases := []string{"", "\x04", "\x1b"}
+
+	for _, keys := range cases {
+		_, err := EditorFor(keys).EditLine("> ")
+
+		if err != io.EOF {
+			t.Logf("EditLine() of %q expects io.EOF received %v", keys, err)
+			t.Fail()
+		}
+	}
+}
+
+func Test_EditLine_Interrupted(t *testing.T) {
+	editor := EditorFor("print 2;\x03print 1;\r")
+
+	if _, err := editor.EditLine("> "); err != ErrInterrupted {
+		t.Fatalf("EditLine() expects ErrInterrupted received %v", err)
+	}
+
+	line, err := editor.EditLine("> ")
+
+	if err != nil || line != "print 1;" {
+		t.Fatalf("EditLine() expects \"print 1;\" received %q, %v", line, err)
+	}
+}
+
+func Test_EditLine_History(t *testing.T) {
+	history := []string{"print 1;", "print 2;", "print 3;"}
+
+	cases := map[string]string{
+		// Moving through the history
+		"\x1b[A\r":                         "print 3;",
+		"\x1b[A\x1b[A\r":                   "print 2;",
+		"\x1b[A\x1b[A\x1b[A\x1b[A\x1b[A\r": "print 1;",
+		"\x10\x10\r":                       "print 2;",
+		"\x1b[A\x1b[A\x1b[B\r":             "print 3;",
+		"\x10\x10\x0e\r":                   "print 3;",
+		// Editing history entries
+		"\x1b[A\x7f\x7f4;\r": "print 4;",
+		// Restoring the edited line
+		"print\x1b[A\x1b[B\r": "print",
+		"print\x1b[B\x1b[B\r": "print",
+		// Reverse search
+		"\x12\r":                  "",
+		"\x122\r":                 "print 2;",
+		"\x12print\r":             "print 3;",
+		"\x12print\x12\r":         "print 2;",
+		"\x12print\x12\x12\x12\r": "print 1;",
+		"\x121\x7f2\r":            "print 2;",
+		"\x122\x1b[D\x1b[D0\r":    "print 02;",
+		"\x12none\r":              "",
+		"old\x122\x07\r":          "old",
+		"\x122\x1b[A\r":           "print 1;",
+	}
+
+	for keys, expected := range cases {
+		line, err := EditorFor(keys, history...).EditLine("> ")
+
+		if err != nil || line != expected {
+			t.Logf(
+				"EditLine() of %q expects %q received %q, %v",
+				keys,
+				expected,
+				line,
+				err,
+			)
+			t.Fail()
+		}
+	}
+}
+
+func Test_ReadLine_NotATerminal(t *testing.T) {
+	editor := EditorFor("print 1;\r\nprint 2;")
+
+	for _, expected := range []string{"print 1;", "print 2;"} {
+		line, err := editor.ReadLine("> ")
+
+		if err != nil || line != expected {
+			t.Logf("ReadLine() expects %q received %q, %v", expected, line, err)
+			t.Fail()
+		}
+	}
+
+	if _, err := editor.ReadLine("> "); err != io.EOF {
+		t.Logf("ReadLine() expects io.EOF received %v", err)
+		t.Fail()
+	}
+
+	if editor.history.Len() != 0 {
+		t.Logf("ReadLine() expects lines not to be added to the history")
+		t.Fail()
+	}
+}
+
+func Test_EditLine_Complete(t *testing.T) {
+	completions := []string{"print", "prompt", "var", "while"}
+
+	completer := func(before string, word string) []string {
+		if before == "no " {
+			return nil
+		}
+		return completions
+	}
+
+	cases := map[string]string{
+		// Unique completions
+		"v\t x;\r":         "var x;",
+		"wh\t (x)\r":       "while (x)",
+		"var\t x;\r":       "var x;",
+		"x = 1; wh\t\r":    "x = 1; while",
+		"(v\t\r":           "(var",
+		"v x;\x01\x06\t\r": "var x;",
+		// Ambiguous completions
+		"p\tint\r":  "print",
+		"pr\tint\r": "print",
+		"pri\t\r":   "print",
+		// No completions
+		"\t\r":     "",
+		"x\t\r":    "x",
+		"no p\t\r": "no p",
+	}
+
+	for keys, expected := range cases {
+		editor := EditorFor(keys)
+		editor.completer = completer
+
+		line, err := editor.EditLine("> ")
+
+		if err != nil || line != expected {
+			t.Logf(
+				"EditLine() of %q expects %q received %q, %v",
+				keys,
+				expected,
+				line,
+				err,
+			)
+			t.Fail()
+		}
+	}
+}
+
+func Test_CommonPrefix(t *testing.T) {
+	cases := map[string][]string{
+		"print": {"print"},
+		"pr":    {"print", "prompt"},
+		"":      {"print", "var"},
+		"var":   {"var", "variable", "var_"},
+	}
+
+	for expected, words := range cases {
+		if prefix := CommonPrefix(words); prefix != expected {
+			t.Logf("CommonPrefix(%q) expects %q received %q", words, expected, prefix)
+			t.Fail()
+		}
+	}
+}
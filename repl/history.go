@@ -0,0 +1,133 @@
+package repl
+
+import "io/ioutil"
+import "os"
+import "path/filepath"
+import "strings"
+
+// The maximum number of lines kept in the history.
+const HistorySize = 1000
+
+// The name of the history file in the user's home directory.
+const HistoryFile = ".golox_history"
+
+// History stores the lines entered into the REPL, oldest first.
+type History struct {
+	lines []string
+	path  string
+
+	// The number of lines at the end added since the history was loaded
+	added int
+}
+
+// Return the path of the history file in the user's home directory, or "" if
+// the home directory is unknown.
+func HistoryPath() string {
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, HistoryFile)
+}
+
+// LoadHistory returns the history stored in the given file.
+// If the file does not exist the history is empty, if the path is "" the
+// history is not persisted.
+func LoadHistory(path string) *History {
+	history := &History{lines: make([]string, 0), path: path}
+
+	if path == "" {
+		return history
+	}
+
+	contents, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return history
+	}
+
+	for _, line := range strings.Split(string(contents), "\n") {
+		history.Add(line)
+	}
+
+	history.added = 0
+
+	return history
+}
+
+// Save appends the lines added since the history was loaded to its file, if it
+// has one.
+// The file is read again before writing, so the lines saved by other sessions
+// since this history was loaded are kept.
+func (h *History) Save() error {
+	if h.path == "" || h.added == 0 {
+		return nil
+	}
+
+	saved := LoadHistory(h.path)
+
+	for _, line := range h.lines[len(h.lines)-h.added:] {
+		saved.Add(line)
+	}
+
+	contents := strings.Join(saved.lines, "\n") + "\n"
+
+	if err := ioutil.WriteFile(h.path, []byte(contents), 0600); err != nil {
+		return err
+	}
+
+	h.added = 0
+
+	return nil
+}
+
+// Add the given line to the history.
+// Blank lines and lines repeating the previous line are not added.
+func (h *History) Add(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	if len(h.lines) > 0 && h.lines[len(h.lines)-1] == line {
+		return
+	}
+
+	h.lines = append(h.lines, line)
+	h.added++
+
+	if len(h.lines) > HistorySize {
+		h.lines = h.lines[len(h.lines)-HistorySize:]
+	}
+
+	if h.added > len(h.lines) {
+		h.added = len(h.lines)
+	}
+}
+
+// Return the number of lines in the history.
+func (h *History) Len() int {
+	return len(h.lines)
+}
+
+// Return the line at the given index, oldest first.
+func (h *History) At(index int) string {
+	return h.lines[index]
+}
+
+// Search returns the index of the newest line at or before the given index
+// containing the query, or -1 if there is none.
+func (h *History) Search(query string, from int) int {
+	if from >= len(h.lines) {
+		from = len(h.lines) - 1
+	}
+
+	for i := from; i >= 0; i-- {
+		if strings.Contains(h.lines[i], query) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,128 @@
+package repl
+
+import "os"
+import "path/filepath"
+import "testing"
+
+func Test_History_Add(t *testing.T) {
+	history := LoadHistory("")
+
+	lines := []string{"print 1;", "", "  ", "print 1;", "print 2;", "print 1;"}
+
+	for _, line := range lines {
+		history.Add(line)
+	}
+
+	expected := []string{"print 1;", "print 2;", "print 1;"}
+
+	if history.Len() != len(expected) {
+		t.Fatalf("Add() expects %d lines received %d", len(expected), history.Len())
+	}
+
+	for i, line := range expected {
+		if history.At(i) != line {
+			t.Logf("At(%d) expects %q received %q", i, line, history.At(i))
+			t.Fail()
+		}
+	}
+
+	// The oldest lines are dropped
+	for i := 0; i < HistorySize*2; i++ {
+		history.Add(string(rune('a' + i%2)))
+	}
+
+	if history.Len() != HistorySize {
+		t.Logf("Add() expects at most %d lines received %d", HistorySize, history.Len())
+		t.Fail()
+	}
+}
+
+func Test_History_Search(t *testing.T) {
+	history := LoadHistory("")
+	history.Add("var a = 1;")
+	history.Add("print a;")
+	history.Add("var b = 2;")
+
+	cases := map[[2]interface{}]int{
+		{"var", 99}: 2,
+		{"var", 2}:  2,
+		{"var", 1}:  0,
+		{"var", 0}:  0,
+		{"var", -1}: -1,
+		{"a;", 2}:   1,
+		{"", 2}:     2,
+		{"none", 2}: -1,
+	}
+
+	for query, expected := range cases {
+		index := history.Search(query[0].(string), query[1].(int))
+
+		if index != expected {
+			t.Logf("Search(%q, %d) expects %d received %d", query[0], query[1], expected, index)
+			t.Fail()
+		}
+	}
+}
+
+func Test_History_Persisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFile)
+
+	// A missing file is an empty history
+	history := LoadHistory(path)
+
+	if history.Len() != 0 {
+		t.Fatalf("LoadHistory() expects an empty history")
+	}
+
+	history.Add("print 1;")
+	history.Add("print 2;")
+
+	if err := history.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := LoadHistory(path)
+
+	if loaded.Len() != 2 || loaded.At(0) != "print 1;" || loaded.At(1) != "print 2;" {
+		t.Logf("LoadHistory() expects the saved lines, received %q", loaded.lines)
+		t.Fail()
+	}
+}
+
+func Test_History_SaveConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFile)
+
+	// Two sessions loading the same history
+	first := LoadHistory(path)
+	second := LoadHistory(path)
+
+	first.Add("print 1;")
+	second.Add("print 2;")
+
+	for _, history := range []*History{first, second} {
+		if err := history.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded := LoadHistory(path)
+
+	if loaded.Len() != 2 || loaded.At(0) != "print 1;" || loaded.At(1) != "print 2;" {
+		t.Logf("Save() expects the lines of both sessions, received %q", loaded.lines)
+		t.Fail()
+	}
+
+	// A history without new lines leaves the file as it is
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loaded.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Logf("Save() expects no file to be written without new lines")
+		t.Fail()
+	}
+}
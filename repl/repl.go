@@ -1,27 +1,116 @@
 package repl
 
-import "bufio"
-import "fmt"
-import "os"
+import "io"
 import "golox/interpreter"
+import "golox/lexer"
+import "strings"
 
-func RunPrompt() {
-	reader := bufio.NewReader(os.Stdin)
+const (
+	Prompt             = "> "
+	ContinuationPrompt = "... "
+)
 
-	for true {
-		fmt.Print("> ")
+// Repl stores the state of a REPL session.
+type Repl struct {
+	session      *interpreter.Interpreter
+	reader       io.Reader
+	writer       io.Writer
+	error_writer io.Writer
+	quit         bool
 
-		line, err := reader.ReadString('\n')
+	// True if the session is served over a socket, where commands reading the
+	// server's files are refused
+	remote bool
+}
+
+// Return a new interpreter reading input from the given reader, writing output
+// to the given writer and errors to the given error writer.
+func NewInterpreter(reader io.Reader, writer io.Writer, error_writer io.Writer) *interpreter.Interpreter {
+	return interpreter.New(interpreter.Options{
+		Stdin:  reader,
+		Stdout: writer,
+		Stderr: error_writer,
+	})
+}
+
+// RunPrompt runs a REPL session reading from the given reader, writing output
+// to the given writer and errors to the given error writer, keeping the history in the user's home directory.
+// An error is returned if reading fails, the session ends without error at the
+// end of input.
+func RunPrompt(reader io.Reader, writer io.Writer, error_writer io.Writer) error {
+	history := LoadHistory(HistoryPath())
+	defer history.Save()
+
+	return RunSession(reader, writer, error_writer, history)
+}
+
+// RunSession runs a REPL session reading from the given reader, writing output
+// to the given writer and errors to the given error writer, adding the lines
+// edited to the given history.
+func RunSession(reader io.Reader, writer io.Writer, error_writer io.Writer, history *History) error {
+	return runSession(reader, writer, error_writer, history, false)
+}
+
+// Run a REPL session as per RunSession, refusing commands which read the
+// server's files if the session is remote.
+func runSession(reader io.Reader, writer io.Writer, error_writer io.Writer, history *History, remote bool) error {
+	editor := NewEditor(reader, writer, history)
+
+	// The session reads through the editor's buffer, so input read ahead by the
+	// editor is not lost
+	repl := Repl{
+		session:      NewInterpreter(editor.reader, writer, error_writer),
+		reader:       editor.reader,
+		writer:       writer,
+		error_writer: error_writer,
+		remote:       remote,
+	}
+	editor.completer = repl.Complete
+
+	source := ""
+
+	for !repl.quit {
+		prompt := Prompt
+		if source != "" {
+			prompt = ContinuationPrompt
+		}
+
+		line, err := editor.ReadLine(prompt)
+
+		if err == ErrInterrupted {
+			source = ""
+			continue
+		}
+
+		// Run the incomplete statement, without the newline added after its last
+		// line, reporting why it is incomplete
+		if err == io.EOF && source != "" {
+			repl.session.Run(strings.TrimSuffix(source, "\n"))
+		}
+
+		if err == io.EOF {
+			return nil
+		}
 
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(65)
+			return err
+		}
+
+		if source == "" && IsCommand(line) {
+			repl.RunCommand(line)
+			continue
 		}
 
-		if line == "\n" {
-			break
+		// Wait for the rest of an incomplete statement
+		source += line + "\n"
+
+		if !lexer.IsComplete(source) {
+			continue
 		}
 
-		interpreter.Run(line)
+		repl.session.Run(source)
+		source = ""
 	}
+
+	return nil
 }
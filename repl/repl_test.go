@@ -0,0 +1,75 @@
+package repl
+
+import "bytes"
+import "strings"
+import "testing"
+
+func Test_RunSession_Continuation(t *testing.T) {
+	var output bytes.Buffer
+
+	err := RunSession(strings.NewReader("fun f() {\n}\n"), &output, &output, LoadHistory(""))
+
+	if err != nil {
+		t.Fatalf("expect no error, received %s", err)
+	}
+
+	// assert the second line is read after the continuation prompt
+	if !strings.HasPrefix(output.String(), Prompt+ContinuationPrompt) {
+		t.Fatalf("expect the continuation prompt, received %q", output.String())
+	}
+
+	// assert the source is run once both lines are read
+	if n := strings.Count(output.String(), "Lexeme(type=Fun"); n != 1 {
+		t.Fatalf("expect the source to run once, ran %d times: %q", n, output.String())
+	}
+}
+
+func Test_RunSession_IncompleteAtEOF(t *testing.T) {
+	var output, errors bytes.Buffer
+
+	err := RunSession(strings.NewReader("print \"abc"), &output, &errors, LoadHistory(""))
+
+	if err != nil {
+		t.Fatalf("expect no error, received %s", err)
+	}
+
+	if !strings.Contains(errors.String(), "Unterminated string.") {
+		t.Fatalf("expect the incomplete source to be run, received %q", errors.String())
+	}
+}
+
+func Test_RunSession_Errors(t *testing.T) {
+	var output, errors bytes.Buffer
+
+	input := "print @;\n:x\n:load missing.lox\nprint 1;\n"
+	err := RunSession(strings.NewReader(input), &output, &errors, LoadHistory(""))
+
+	if err != nil {
+		t.Fatalf("expect no error, received %s", err)
+	}
+
+	// assert the errors are written to the error writer only
+	expected := []string{
+		"SyntaxError: line 1: Unexpected character '@'",
+		"Unknown command ':x', see :help",
+		"missing.lox",
+	}
+
+	for _, message := range expected {
+		if !strings.Contains(errors.String(), message) {
+			t.Logf("expect %q on the error writer, received %q", message, errors.String())
+			t.Fail()
+		}
+
+		if strings.Contains(output.String(), message) {
+			t.Logf("expect %q not on the writer, received %q", message, output.String())
+			t.Fail()
+		}
+	}
+
+	// assert the output is written to the writer only
+	if !strings.Contains(output.String(), "Lexeme(type=LiteralInteger") || strings.Contains(errors.String(), "Lexeme") {
+		t.Logf("expect lexemes on the writer only, received %q and %q", output.String(), errors.String())
+		t.Fail()
+	}
+}
@@ -0,0 +1,46 @@
+package repl
+
+import "fmt"
+import "net"
+import "strings"
+
+// Listen returns a listener on the given address, either "unix:path" for a Unix
+// socket or "tcp:host:port" for a TCP socket.
+func Listen(address string) (net.Listener, error) {
+	i := strings.Index(address, ":")
+
+	if i == -1 {
+		return nil, fmt.Errorf("address '%s' is not unix:path or tcp:host:port", address)
+	}
+
+	network, location := address[:i], address[i+1:]
+
+	switch network {
+	case "unix", "tcp":
+		return net.Listen(network, location)
+	default:
+		return nil, fmt.Errorf("network '%s' is not unix or tcp", network)
+	}
+}
+
+// Serve runs an independent REPL session for each connection accepted on the
+// given listener, until accepting a connection fails.
+// The history of each session is not persisted, and :load is refused as it
+// would read the server's files.
+func Serve(listener net.Listener) error {
+	for true {
+		connection, err := listener.Accept()
+
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer connection.Close()
+
+			runSession(connection, connection, connection, LoadHistory(""), true)
+		}()
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package repl
+
+import "io/ioutil"
+import "net"
+import "path/filepath"
+import "strings"
+import "testing"
+
+func Test_Listen_Invalid(t *testing.T) {
+	addresses := []string{"", "localhost", "udp:127.0.0.1:0", "tcp:not an address"}
+
+	for _, address := range addresses {
+		if listener, err := Listen(address); err == nil {
+			listener.Close()
+			t.Logf("Listen(%q) expects error", address)
+			t.Fail()
+		}
+	}
+}
+
+// Send the given input to a REPL session on the listener, returning the output.
+func SendTo(listener net.Listener, input string) (string, error) {
+	address := listener.Addr()
+	connection, err := net.Dial(address.Network(), address.String())
+
+	if err != nil {
+		return "", err
+	}
+
+	defer connection.Close()
+
+	if _, err := connection.Write([]byte(input)); err != nil {
+		return "", err
+	}
+
+	output, err := ioutil.ReadAll(connection)
+
+	return string(output), err
+}
+
+func Test_Serve(t *testing.T) {
+	directory := t.TempDir()
+	path := filepath.Join(directory, "script.lox")
+
+	if err := ioutil.WriteFile(path, []byte("print hidden;"), 0600); err != nil {
+		t.Fatalf("error writing script: %s", err)
+	}
+
+	addresses := []string{"tcp:127.0.0.1:0", "unix:" + filepath.Join(directory, "lox.sock")}
+
+	for _, address := range addresses {
+		listener, err := Listen(address)
+
+		if err != nil {
+			t.Fatalf("Listen(%q) failed with error: %s", address, err)
+		}
+
+		go Serve(listener)
+
+		// Sessions with and without errors
+		inputs := map[string]string{
+			"print 1;\n:quit\n": "Lexeme(type=LiteralInteger, lexeme=\"1\", literal=1)",
+			"print @;\n:quit\n": "SyntaxError: line 1: Unexpected character '@'",
+			// Files on the server are not readable
+			":load " + path + "\n:quit\n": ":load is disabled for network sessions",
+		}
+
+		outputs := make(chan [2]string, len(inputs)*10)
+
+		for i := 0; i < 10; i++ {
+			for input := range inputs {
+				go func(input string) {
+					output, err := SendTo(listener, input)
+
+					if err != nil {
+						output = err.Error()
+					}
+
+					outputs <- [2]string{input, output}
+				}(input)
+			}
+		}
+
+		for i := 0; i < len(inputs)*10; i++ {
+			result := <-outputs
+			input, output := result[0], result[1]
+
+			if !strings.Contains(output, inputs[input]) {
+				t.Logf("session of %q on %s expects %q in %q", input, address, inputs[input], output)
+				t.Fail()
+			}
+
+			if strings.Contains(output, "hidden") {
+				t.Logf("session of %q on %s expects the file not to be read in %q", input, address, output)
+				t.Fail()
+			}
+
+			if input == "print 1;\n:quit\n" && strings.Contains(output, "Error") {
+				t.Logf("session of %q on %s expects no errors in %q", input, address, output)
+				t.Fail()
+			}
+		}
+
+		listener.Close()
+	}
+}
@@ -0,0 +1,11 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package repl
+
+import "syscall"
+
+const (
+	get_termios = syscall.TIOCGETA
+	set_termios = syscall.TIOCSETA
+)
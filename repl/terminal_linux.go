@@ -0,0 +1,11 @@
+//go:build linux
+// +build linux
+
+package repl
+
+import "syscall"
+
+const (
+	get_termios = syscall.TCGETS
+	set_termios = syscall.TCSETS
+)
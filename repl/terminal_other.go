@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package repl
+
+import "errors"
+
+// MakeRaw is not supported on this platform, lines are read without editing.
+func MakeRaw(fd int) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported")
+}
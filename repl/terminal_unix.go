@@ -0,0 +1,50 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package repl
+
+import "syscall"
+import "unsafe"
+
+// MakeRaw puts the terminal with the given file descriptor into raw mode, so
+// key presses are read as they are typed and are not echoed.
+// The returned function restores the terminal to its previous mode.
+// An error is returned if the file descriptor is not a terminal.
+func MakeRaw(fd int) (func(), error) {
+	var previous syscall.Termios
+
+	if err := ioctl(fd, get_termios, &previous); err != nil {
+		return nil, err
+	}
+
+	raw := previous
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+
+	if err := ioctl(fd, set_termios, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() { ioctl(fd, set_termios, &previous) }, nil
+}
+
+// Get or set the terminal attributes of the given file descriptor.
+func ioctl(fd int, request uintptr, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		uintptr(fd),
+		request,
+		uintptr(unsafe.Pointer(termios)),
+	)
+
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}